@@ -0,0 +1,150 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+)
+
+const (
+	LabelActuatorHealth     = "org.springframework.boot.actuator.health"
+	LabelActuatorLiveness   = "org.springframework.boot.actuator.liveness"
+	LabelActuatorReadiness  = "org.springframework.boot.actuator.readiness"
+	LabelActuatorPrometheus = "org.springframework.boot.actuator.prometheus"
+)
+
+// Actuator describes the Spring Boot Actuator endpoints an application exposes over HTTP, as configured in its
+// packaged configuration.
+type Actuator struct {
+	Configuration   ApplicationConfiguration
+	Dependencies    []Dependency
+	ApplicationType ApplicationType
+	Version         string
+}
+
+// Labels returns image labels with the URLs of the health, liveness, readiness and Prometheus endpoints, relative to
+// the container. No labels are returned when Actuator is absent or its endpoints are not available over HTTP.
+func (a Actuator) Labels() []libcnb.Label {
+	if a.ApplicationType == None || !FindExistingDependency(a.Dependencies, "spring-boot-actuator") {
+		return nil
+	}
+
+	port, ok := a.port()
+	if !ok {
+		return nil
+	}
+
+	base := a.get("management.endpoints.web.base-path", "/actuator")
+	if a.separateManagementPort() {
+		base = path.Join("/", a.get("management.server.base-path", a.get("management.server.servlet.context-path", "")), base)
+	} else if a.ApplicationType == Reactive {
+		base = path.Join("/", a.get("spring.webflux.base-path", ""), base)
+	} else {
+		base = path.Join("/", a.get("server.servlet.context-path", ""), base)
+	}
+
+	url := func(endpoint string, group string) string {
+		id := a.get(fmt.Sprintf("management.endpoints.web.path-mapping.%s", endpoint), endpoint)
+		return fmt.Sprintf("http://localhost:%d%s", port, path.Join(base, id, group))
+	}
+
+	var labels []libcnb.Label
+	if a.exposed("health") {
+		labels = append(labels, libcnb.Label{Key: LabelActuatorHealth, Value: url("health", "")})
+
+		if a.probesEnabled() {
+			labels = append(labels,
+				libcnb.Label{Key: LabelActuatorLiveness, Value: url("health", "liveness")},
+				libcnb.Label{Key: LabelActuatorReadiness, Value: url("health", "readiness")},
+			)
+		}
+	}
+
+	if FindExistingDependency(a.Dependencies, "micrometer-registry-prometheus") && a.exposed("prometheus") {
+		labels = append(labels, libcnb.Label{Key: LabelActuatorPrometheus, Value: url("prometheus", "")})
+	}
+
+	return labels
+}
+
+func (a Actuator) get(key string, def string) string {
+	if v, ok := a.Configuration.Get(key); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+func (a Actuator) separateManagementPort() bool {
+	_, ok := a.Configuration.Get("management.server.port")
+	return ok
+}
+
+// port returns the port management endpoints are served on. Actuator disables them over HTTP with a port of -1.
+func (a Actuator) port() (int, bool) {
+	s := a.get("server.port", "8080")
+	if a.separateManagementPort() {
+		s = a.get("management.server.port", s)
+	}
+
+	port, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || port < 0 {
+		return 0, false
+	}
+	if port == 0 {
+		// a random port cannot be probed
+		return 0, false
+	}
+
+	return port, true
+}
+
+// probesEnabled returns whether liveness and readiness health groups are available. Since Spring Boot 2.3.2 they are
+// enabled automatically when the application runs on Kubernetes, unless explicitly disabled.
+func (a Actuator) probesEnabled() bool {
+	if s, ok := a.Configuration.Get("management.endpoint.health.probes.enabled"); ok {
+		enabled, err := strconv.ParseBool(strings.TrimSpace(s))
+		return err == nil && enabled
+	}
+
+	return versionRespectsConstraint(a.Version, ">= 2.3.2")
+}
+
+func (a Actuator) exposed(endpoint string) bool {
+	for _, s := range a.Configuration.List("management.endpoints.web.exposure.exclude") {
+		if s == endpoint || s == "*" {
+			return false
+		}
+	}
+
+	include := a.Configuration.List("management.endpoints.web.exposure.include")
+	if len(include) == 0 {
+		include = []string{"health"}
+	}
+
+	for _, s := range include {
+		if s == endpoint || s == "*" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testActuator(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		a boot.Actuator
+	)
+
+	it.Before(func() {
+		a = boot.Actuator{
+			Configuration:   boot.ApplicationConfiguration{Properties: map[string]string{}},
+			Dependencies:    []boot.Dependency{{Name: "spring-boot-actuator", Version: "3.2.1"}},
+			ApplicationType: boot.Servlet,
+			Version:         "3.2.1",
+		}
+	})
+
+	it("does not contribute labels without actuator", func() {
+		a.Dependencies = nil
+
+		Expect(a.Labels()).To(BeEmpty())
+	})
+
+	it("does not contribute labels for non-web applications", func() {
+		a.ApplicationType = boot.None
+
+		Expect(a.Labels()).To(BeEmpty())
+	})
+
+	it("contributes default labels", func() {
+		Expect(a.Labels()).To(Equal([]libcnb.Label{
+			{Key: "org.springframework.boot.actuator.health", Value: "http://localhost:8080/actuator/health"},
+			{Key: "org.springframework.boot.actuator.liveness", Value: "http://localhost:8080/actuator/health/liveness"},
+			{Key: "org.springframework.boot.actuator.readiness", Value: "http://localhost:8080/actuator/health/readiness"},
+		}))
+	})
+
+	it("does not contribute probe labels before Spring Boot 2.3.2", func() {
+		a.Version = "2.3.1"
+
+		Expect(a.Labels()).To(Equal([]libcnb.Label{
+			{Key: "org.springframework.boot.actuator.health", Value: "http://localhost:8080/actuator/health"},
+		}))
+	})
+
+	it("honors probes configuration", func() {
+		a.Version = "2.2.0"
+		a.Configuration.Properties["management.endpoint.health.probes.enabled"] = "true"
+
+		Expect(a.Labels()).To(HaveLen(3))
+
+		a.Version = "3.2.1"
+		a.Configuration.Properties["management.endpoint.health.probes.enabled"] = "false"
+
+		Expect(a.Labels()).To(HaveLen(1))
+	})
+
+	it("uses server port and context path", func() {
+		a.Configuration.Properties["server.port"] = "9090"
+		a.Configuration.Properties["server.servlet.context-path"] = "/app"
+		a.Configuration.Properties["management.endpoints.web.base-path"] = "/manage"
+
+		Expect(a.Labels()).To(ContainElement(libcnb.Label{Key: "org.springframework.boot.actuator.health", Value: "http://localhost:9090/app/manage/health"}))
+	})
+
+	it("uses management port without context path", func() {
+		a.Configuration.Properties["server.port"] = "9090"
+		a.Configuration.Properties["server.servlet.context-path"] = "/app"
+		a.Configuration.Properties["management.server.port"] = "9091"
+
+		Expect(a.Labels()).To(ContainElement(libcnb.Label{Key: "org.springframework.boot.actuator.health", Value: "http://localhost:9091/actuator/health"}))
+	})
+
+	it("does not contribute labels when management port is disabled", func() {
+		a.Configuration.Properties["management.server.port"] = "-1"
+
+		Expect(a.Labels()).To(BeEmpty())
+	})
+
+	it("does not contribute health labels when health is excluded", func() {
+		a.Configuration.Properties["management.endpoints.web.exposure.exclude"] = "health"
+
+		Expect(a.Labels()).To(BeEmpty())
+	})
+
+	it("does not contribute health labels when health is not included", func() {
+		a.Dependencies = append(a.Dependencies, boot.Dependency{Name: "micrometer-registry-prometheus", Version: "1.12.1"})
+		a.Configuration.Properties["management.endpoints.web.exposure.include"] = "prometheus"
+
+		Expect(a.Labels()).To(Equal([]libcnb.Label{
+			{Key: "org.springframework.boot.actuator.prometheus", Value: "http://localhost:8080/actuator/prometheus"},
+		}))
+	})
+
+	context("prometheus", func() {
+		it.Before(func() {
+			a.Version = "2.3.1"
+			a.Dependencies = append(a.Dependencies, boot.Dependency{Name: "micrometer-registry-prometheus", Version: "1.12.1"})
+		})
+
+		it("does not contribute label when not exposed", func() {
+			Expect(a.Labels()).To(HaveLen(1))
+		})
+
+		it("contributes label when exposed", func() {
+			a.Configuration.Properties["management.endpoints.web.exposure.include"] = "health, prometheus"
+
+			Expect(a.Labels()).To(ContainElement(libcnb.Label{Key: "org.springframework.boot.actuator.prometheus", Value: "http://localhost:8080/actuator/prometheus"}))
+		})
+
+		it("contributes label when all endpoints are exposed", func() {
+			a.Configuration.Properties["management.endpoints.web.exposure.include"] = "*"
+
+			Expect(a.Labels()).To(ContainElement(libcnb.Label{Key: "org.springframework.boot.actuator.prometheus", Value: "http://localhost:8080/actuator/prometheus"}))
+		})
+
+		it("does not contribute label when excluded", func() {
+			a.Configuration.Properties["management.endpoints.web.exposure.include"] = "*"
+			a.Configuration.Properties["management.endpoints.web.exposure.exclude"] = "prometheus"
+
+			Expect(a.Labels()).To(HaveLen(1))
+		})
+	})
+}
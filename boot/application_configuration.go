@@ -0,0 +1,500 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfile is active when no other profile is.
+const DefaultProfile = "default"
+
+var (
+	configurationPlaceholder      = regexp.MustCompile(`\$\{([^}:]+)(?::([^}]*))?\}`)
+	configurationDocumentSplitter = regexp.MustCompile(`(?m)^[#!]---[ \t]*\r?$`)
+	configurationIndex            = regexp.MustCompile(`\[(\d+)\]$`)
+)
+
+// configurationExtensions are listed from lowest to highest precedence within a location.
+var configurationExtensions = []string{".yaml", ".yml", ".properties"}
+
+// ConfigurationDocument is a single document of an application configuration file. Multi-document files are split on
+// --- in YAML and #--- in properties files.
+type ConfigurationDocument struct {
+	// Origin is the file the document was read from.
+	Origin string
+
+	// Profile is the profile of a profile-specific file, application-<profile>.properties, and empty otherwise.
+	Profile string
+
+	// Properties are the flattened properties of the document.
+	Properties map[string]string
+}
+
+// OnProfile returns the profile expression that activates the document, from spring.config.activate.on-profile or
+// the legacy spring.profiles.
+func (d ConfigurationDocument) OnProfile() string {
+	for _, key := range []string{"spring.config.activate.on-profile", "spring.profiles"} {
+		if v := configurationList(d.Properties, key); len(v) > 0 {
+			return strings.Join(v, ",")
+		}
+	}
+	return ""
+}
+
+// ApplicationConfiguration is the effective configuration packaged with an application in application.properties,
+// application.yml and their profile-specific variants.
+type ApplicationConfiguration struct {
+	// Profiles are the active profiles the configuration was resolved for.
+	Profiles []string
+
+	// Properties are the effective properties.
+	Properties map[string]string
+}
+
+// NewApplicationConfiguration loads the application configuration found in classes, as it would be resolved with
+// profiles active. When no profiles are given, those activated by the configuration itself are used.
+func NewApplicationConfiguration(classes string, profiles ...string) (ApplicationConfiguration, error) {
+	documents, err := LoadConfigurationDocuments(classes)
+	if err != nil {
+		return ApplicationConfiguration{}, err
+	}
+
+	return ResolveApplicationConfiguration(documents, profiles...), nil
+}
+
+// LoadConfigurationDocuments reads every document of the application configuration files found in classes, in the
+// order Spring Boot applies them for a single profile.
+func LoadConfigurationDocuments(classes string) ([]ConfigurationDocument, error) {
+	var documents []ConfigurationDocument
+
+	for _, ext := range configurationExtensions {
+		d, err := loadConfigurationFile(filepath.Join(classes, "application"+ext), "")
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, d...)
+	}
+
+	var files []string
+	for _, ext := range configurationExtensions {
+		matches, err := filepath.Glob(filepath.Join(classes, "application-*"+ext))
+		if err != nil {
+			return nil, fmt.Errorf("unable to glob %s\n%w", classes, err)
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	for _, ext := range configurationExtensions {
+		for _, file := range files {
+			if filepath.Ext(file) != ext {
+				continue
+			}
+
+			profile := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), "application-"), ext)
+			d, err := loadConfigurationFile(file, profile)
+			if err != nil {
+				return nil, err
+			}
+			documents = append(documents, d...)
+		}
+	}
+
+	return documents, nil
+}
+
+// ResolveApplicationConfiguration merges documents into the effective configuration with profiles active. When no
+// profiles are given, they are taken from spring.profiles.active and spring.profiles.include, falling back to
+// spring.profiles.default. Profile groups are expanded.
+func ResolveApplicationConfiguration(documents []ConfigurationDocument, profiles ...string) ApplicationConfiguration {
+	base := map[string]string{}
+	for _, d := range documents {
+		if d.Profile == "" && d.OnProfile() == "" {
+			mergeConfiguration(base, d.Properties)
+		}
+	}
+
+	if len(profiles) == 0 {
+		profiles = configurationList(base, "spring.profiles.active")
+	}
+	profiles = append(append([]string{}, profiles...), configurationList(base, "spring.profiles.include")...)
+	if len(profiles) == 0 {
+		if profiles = configurationList(base, "spring.profiles.default"); len(profiles) == 0 {
+			profiles = []string{DefaultProfile}
+		}
+	}
+	profiles = expandProfileGroups(base, profiles)
+
+	a := ApplicationConfiguration{Profiles: profiles, Properties: map[string]string{}}
+
+	apply := func(d ConfigurationDocument) {
+		if e := d.OnProfile(); e == "" || ProfilesMatch(e, profiles) {
+			mergeConfiguration(a.Properties, d.Properties)
+		}
+	}
+
+	for _, d := range documents {
+		if d.Profile == "" {
+			apply(d)
+		}
+	}
+	for _, p := range profiles {
+		for _, d := range documents {
+			if d.Profile == p {
+				apply(d)
+			}
+		}
+	}
+
+	return a
+}
+
+// Get returns the effective value of a property, matched using relaxed binding and with ${name:default}
+// placeholders resolved against the configuration. A placeholder that cannot be resolved causes the property to be
+// reported as absent.
+func (a ApplicationConfiguration) Get(key string) (string, bool) {
+	v, ok := lookupConfiguration(a.Properties, key)
+	if !ok {
+		return "", false
+	}
+
+	for i := 0; i < 10 && strings.Contains(v, "${"); i++ {
+		resolved := true
+		v = configurationPlaceholder.ReplaceAllStringFunc(v, func(m string) string {
+			p := configurationPlaceholder.FindStringSubmatch(m)
+			if s, ok := lookupConfiguration(a.Properties, p[1]); ok {
+				return s
+			}
+			if strings.Contains(m, ":") {
+				return p[2]
+			}
+			resolved = false
+			return m
+		})
+		if !resolved {
+			return "", false
+		}
+	}
+
+	return v, true
+}
+
+// List returns the values of a property given either as a comma-separated value or as an indexed list.
+func (a ApplicationConfiguration) List(key string) []string {
+	if v, ok := a.Get(key); ok {
+		return splitConfigurationList(v)
+	}
+	return configurationList(a.Properties, key)
+}
+
+// ProfilesMatch returns whether a profile expression, such as "prod & !cloud" or "dev,test", matches the active
+// profiles.
+func ProfilesMatch(expression string, active []string) bool {
+	for _, e := range strings.Split(expression, ",") {
+		p := profileExpressionParser{tokens: tokenizeProfileExpression(e), active: active}
+		if len(p.tokens) > 0 && p.or() && p.pos == len(p.tokens) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanonicalConfigurationName returns the relaxed binding form of a property name, so that contextPath,
+// context-path, context_path and CONTEXT_PATH are considered equal.
+func CanonicalConfigurationName(name string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range name {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth == 0 && (r == '-' || r == '_'):
+			continue
+		case depth == 0:
+			r = []rune(strings.ToLower(string(r)))[0]
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func lookupConfiguration(values map[string]string, key string) (string, bool) {
+	if v, ok := values[key]; ok {
+		return v, true
+	}
+
+	c := CanonicalConfigurationName(key)
+	for k, v := range values {
+		if CanonicalConfigurationName(k) == c {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+func mergeConfiguration(target map[string]string, source map[string]string) {
+	for k, v := range source {
+		c := CanonicalConfigurationName(k)
+		for existing := range target {
+			if existing != k && CanonicalConfigurationName(existing) == c {
+				delete(target, existing)
+			}
+		}
+		target[k] = v
+	}
+}
+
+func configurationList(values map[string]string, key string) []string {
+	if v, ok := lookupConfiguration(values, key); ok {
+		return splitConfigurationList(v)
+	}
+
+	c := CanonicalConfigurationName(key)
+	indexed := map[int]string{}
+	for k, v := range values {
+		if m := configurationIndex.FindStringSubmatchIndex(k); m != nil && CanonicalConfigurationName(k[:m[0]]) == c {
+			var i int
+			fmt.Sscanf(k[m[2]:m[3]], "%d", &i)
+			indexed[i] = strings.TrimSpace(v)
+		}
+	}
+
+	var list []string
+	for i := 0; i < len(indexed); i++ {
+		if v, ok := indexed[i]; ok && v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func splitConfigurationList(value string) []string {
+	var list []string
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+func expandProfileGroups(values map[string]string, profiles []string) []string {
+	var expanded []string
+	seen := map[string]bool{}
+
+	var expand func(string)
+	expand = func(p string) {
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		expanded = append(expanded, p)
+		for _, member := range configurationList(values, fmt.Sprintf("spring.profiles.group.%s", p)) {
+			expand(member)
+		}
+	}
+
+	for _, p := range profiles {
+		expand(p)
+	}
+	return expanded
+}
+
+func loadConfigurationFile(file string, profile string) ([]ConfigurationDocument, error) {
+	b, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	var values []map[string]string
+	if filepath.Ext(file) == ".properties" {
+		values, err = loadConfigurationProperties(b)
+	} else {
+		values, err = loadConfigurationYAML(b)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to load %s\n%w", file, err)
+	}
+
+	var documents []ConfigurationDocument
+	for _, v := range values {
+		documents = append(documents, ConfigurationDocument{Origin: filepath.Base(file), Profile: profile, Properties: v})
+	}
+	return documents, nil
+}
+
+func loadConfigurationProperties(b []byte) ([]map[string]string, error) {
+	l := properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}
+
+	var documents []map[string]string
+	for _, s := range configurationDocumentSplitter.Split(string(b), -1) {
+		p, err := l.LoadBytes([]byte(s))
+		if err != nil {
+			return nil, err
+		}
+		if p.Len() > 0 {
+			documents = append(documents, p.Map())
+		}
+	}
+
+	return documents, nil
+}
+
+func loadConfigurationYAML(b []byte) ([]map[string]string, error) {
+	var documents []map[string]string
+
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var document interface{}
+		if err := decoder.Decode(&document); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		values := map[string]string{}
+		flattenConfiguration("", document, values)
+		if len(values) > 0 {
+			documents = append(documents, values)
+		}
+	}
+
+	return documents, nil
+}
+
+func flattenConfiguration(prefix string, value interface{}, values map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenConfiguration(key, child, values)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenConfiguration(fmt.Sprintf("%s[%d]", prefix, i), child, values)
+		}
+	case nil:
+		if prefix != "" {
+			values[prefix] = ""
+		}
+	default:
+		values[prefix] = fmt.Sprint(v)
+	}
+}
+
+type profileExpressionParser struct {
+	tokens []string
+	pos    int
+	active []string
+}
+
+func tokenizeProfileExpression(expression string) []string {
+	var tokens []string
+	var name strings.Builder
+
+	flush := func() {
+		if name.Len() > 0 {
+			tokens = append(tokens, name.String())
+			name.Reset()
+		}
+	}
+
+	for _, r := range expression {
+		switch r {
+		case '!', '&', '|', '(', ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case ' ', '\t':
+			flush()
+		default:
+			name.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+func (p *profileExpressionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *profileExpressionParser) or() bool {
+	result := p.and()
+	for p.peek() == "|" {
+		p.pos++
+		result = p.and() || result
+	}
+	return result
+}
+
+func (p *profileExpressionParser) and() bool {
+	result := p.unary()
+	for p.peek() == "&" {
+		p.pos++
+		result = p.unary() && result
+	}
+	return result
+}
+
+func (p *profileExpressionParser) unary() bool {
+	switch t := p.peek(); t {
+	case "!":
+		p.pos++
+		return !p.unary()
+	case "(":
+		p.pos++
+		result := p.or()
+		if p.peek() == ")" {
+			p.pos++
+		}
+		return result
+	case "", ")", "&", "|":
+		return false
+	default:
+		p.pos++
+		for _, a := range p.active {
+			if a == t {
+				return true
+			}
+		}
+		return false
+	}
+}
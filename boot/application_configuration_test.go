@@ -0,0 +1,203 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testApplicationConfiguration(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	get := func(a boot.ApplicationConfiguration, key string) string {
+		v, ok := a.Get(key)
+		Expect(ok).To(BeTrue())
+		return v
+	}
+
+	it.Before(func() {
+		var err error
+
+		path, err = os.MkdirTemp("", "application-configuration")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	it("returns empty configuration without configuration files", func() {
+		Expect(boot.NewApplicationConfiguration(path)).To(Equal(boot.ApplicationConfiguration{
+			Profiles:   []string{"default"},
+			Properties: map[string]string{},
+		}))
+	})
+
+	it("flattens YAML configuration", func() {
+		Expect(os.WriteFile(filepath.Join(path, "application.yml"), []byte(`
+server:
+  port: 9090
+management:
+  endpoints:
+    web:
+      exposure:
+        include:
+        - health
+        - prometheus
+`), 0644)).To(Succeed())
+
+		a, err := boot.NewApplicationConfiguration(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(a.Properties).To(Equal(map[string]string{
+			"server.port": "9090",
+			"management.endpoints.web.exposure.include[0]": "health",
+			"management.endpoints.web.exposure.include[1]": "prometheus",
+		}))
+	})
+
+	it("prefers properties over YAML", func() {
+		Expect(os.WriteFile(filepath.Join(path, "application.yml"), []byte("server.port: 9090\nserver.address: 0.0.0.0"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "application.properties"), []byte("server.port=9091"), 0644)).To(Succeed())
+
+		a, err := boot.NewApplicationConfiguration(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(a.Properties).To(Equal(map[string]string{"server.port": "9091", "server.address": "0.0.0.0"}))
+	})
+
+	it("resolves placeholders", func() {
+		Expect(os.WriteFile(filepath.Join(path, "application.properties"), []byte(`
+server.port=${PORT:8081}
+management.server.port=${server.port}
+unresolvable=${MISSING}
+`), 0644)).To(Succeed())
+
+		a, err := boot.NewApplicationConfiguration(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		v, ok := a.Get("server.port")
+		Expect(ok).To(BeTrue())
+		Expect(v).To(Equal("8081"))
+
+		v, ok = a.Get("management.server.port")
+		Expect(ok).To(BeTrue())
+		Expect(v).To(Equal("8081"))
+
+		_, ok = a.Get("unresolvable")
+		Expect(ok).To(BeFalse())
+	})
+
+	it("matches properties with relaxed binding", func() {
+		Expect(os.WriteFile(filepath.Join(path, "application.yml"), []byte("server.servlet.contextPath: /yaml"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "application.properties"), []byte("server.servlet.context_path=/properties"), 0644)).To(Succeed())
+
+		a, err := boot.NewApplicationConfiguration(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(a.Properties).To(Equal(map[string]string{"server.servlet.context_path": "/properties"}))
+
+		v, ok := a.Get("server.servlet.context-path")
+		Expect(ok).To(BeTrue())
+		Expect(v).To(Equal("/properties"))
+	})
+
+	it("returns lists", func() {
+		a := boot.ApplicationConfiguration{Properties: map[string]string{
+			"comma":      "a, b,c",
+			"indexed[1]": "b",
+			"indexed[0]": "a",
+		}}
+
+		Expect(a.List("comma")).To(Equal([]string{"a", "b", "c"}))
+		Expect(a.List("indexed")).To(Equal([]string{"a", "b"}))
+		Expect(a.List("missing")).To(BeEmpty())
+	})
+
+	context("profiles", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(path, "application.yml"), []byte(`
+server.port: 8080
+spring.profiles.active: prod
+spring.profiles.group.prod: proddb
+---
+spring.config.activate.on-profile: dev
+server.port: 8081
+---
+spring.config.activate.on-profile: "proddb & !cloud"
+server.address: 10.0.0.1
+`), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(path, "application.properties"), []byte(`
+management.server.port=9090
+#---
+spring.profiles=dev
+management.server.port=9091
+`), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(path, "application-prod.properties"), []byte("server.port=8082"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(path, "application-dev.yaml"), []byte("server.port: 8083"), 0644)).To(Succeed())
+		})
+
+		it("activates profiles from the configuration", func() {
+			a, err := boot.NewApplicationConfiguration(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(a.Profiles).To(Equal([]string{"prod", "proddb"}))
+			Expect(get(a, "server.port")).To(Equal("8082"))
+			Expect(get(a, "server.address")).To(Equal("10.0.0.1"))
+			Expect(get(a, "management.server.port")).To(Equal("9090"))
+		})
+
+		it("activates given profiles", func() {
+			a, err := boot.NewApplicationConfiguration(path, "dev")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(a.Profiles).To(Equal([]string{"dev"}))
+			Expect(get(a, "server.port")).To(Equal("8083"))
+			Expect(get(a, "management.server.port")).To(Equal("9091"))
+
+			_, ok := a.Get("server.address")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	it("matches profile expressions", func() {
+		Expect(boot.ProfilesMatch("prod", []string{"prod"})).To(BeTrue())
+		Expect(boot.ProfilesMatch("!prod", []string{"prod"})).To(BeFalse())
+		Expect(boot.ProfilesMatch("dev, prod", []string{"prod"})).To(BeTrue())
+		Expect(boot.ProfilesMatch("prod & cloud", []string{"prod"})).To(BeFalse())
+		Expect(boot.ProfilesMatch("prod & (cloud | local)", []string{"prod", "local"})).To(BeTrue())
+		Expect(boot.ProfilesMatch("dev | test", []string{"prod"})).To(BeFalse())
+	})
+
+	it("returns an error for invalid YAML", func() {
+		Expect(os.WriteFile(filepath.Join(path, "application.yaml"), []byte("server: [port"), 0644)).To(Succeed())
+
+		_, err := boot.NewApplicationConfiguration(path)
+		Expect(err).To(MatchError(ContainSubstring("unable to load")))
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -91,20 +92,45 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 		performanceType = CdsAotCache
 	}
 
+	var exploded ExplodedApplication
+	explodedFound := false
+
+	thinFound, err := IsThinApplication(context.Application.Path, manifest)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to detect Spring Boot Thin Launcher in %s\n%w", context.Application.Path, err)
+	}
+
 	version, versionFound := manifest.Get("Spring-Boot-Version")
-	if !versionFound {
-		if context.Application.Path, manifest, err = b.findSpringBootExecutableJAR(context.Application.Path); err != nil {
+	if !versionFound && !thinFound {
+		appPath := context.Application.Path
+		if context.Application.Path, manifest, err = b.findSpringBootExecutableJAR(appPath); err != nil {
 			return libcnb.BuildResult{}, fmt.Errorf("unable to find Spring Boot Executable Jar\n%w", err)
-		} else {
-			if version, versionFound = manifest.Get("Spring-Boot-Version"); !versionFound {
-				// this isn't a boot app, return without printing title
-				return libcnb.BuildResult{}, nil
+		} else if version, versionFound = manifest.Get("Spring-Boot-Version"); versionFound {
+			bootJarFound = true
+			if thinFound, err = IsThinApplication(context.Application.Path, manifest); err != nil {
+				return libcnb.BuildResult{}, fmt.Errorf("unable to detect Spring Boot Thin Launcher in %s\n%w", context.Application.Path, err)
 			}
+		} else if exploded, explodedFound, err = NewExplodedApplication(appPath, sherpa.GetEnvWithDefault("BP_SPRING_BOOT_MAIN_CLASS", "")); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to inspect exploded application in %s\n%w", appPath, err)
+		} else if !explodedFound {
+			// this isn't a boot app, return without printing title
+			return libcnb.BuildResult{}, nil
+		} else {
+			context.Application.Path, manifest, version = appPath, exploded.Manifest(), exploded.Version
 		}
-		bootJarFound = true
 	}
 	mainClass, _ := manifest.Get("Main-Class")
 
+	if explodedFound || thinFound {
+		if explodedFound && mainClass == "" {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to determine the main class of the exploded application; set BP_SPRING_BOOT_MAIN_CLASS to the class to launch")
+		}
+		if performanceType != Without {
+			b.Logger.Bodyf("You enabled CDS_AOTCACHE optimization or extract mode only but your application is not a Spring Boot executable jar\nCancelling performance optimization")
+			performanceType = Without
+		}
+	}
+
 	if performanceType == CdsAotCache || performanceType == ExtractLayout {
 		if bootCDSExtractionSupported(version) {
 			reZipExplodedJar = true
@@ -117,6 +143,14 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 
 	b.Logger.Title(context.Buildpack)
 
+	if thinFound {
+		if exploded, err = b.resolveThinApplication(context, manifest); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to resolve Spring Boot Thin Launcher dependencies\n%w", err)
+		}
+		explodedFound = true
+		manifest, version, mainClass = exploded.Manifest(), exploded.Version, exploded.MainClass
+	}
+
 	var helpers []string
 
 	dc, err := libpak.NewDependencyCache(context)
@@ -138,24 +172,126 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 	}
 
 	// add labels
-	result.Labels, err = labels(context.Application.Path, manifest)
-	if err != nil {
-		return libcnb.BuildResult{}, err
+	classesDir, _ := manifest.Get("Spring-Boot-Classes")
+	provenance := Provenance{
+		ApplicationPath:   context.Application.Path,
+		Classes:           classesDir,
+		GitDisabled:       cr.ResolveBool("BP_SPRING_BOOT_GIT_LABELS_DISABLED"),
+		BuildInfoDisabled: cr.ResolveBool("BP_SPRING_BOOT_BUILD_INFO_LABELS_DISABLED"),
 	}
-
-	// add dependencies to BOM
 	lib, ok := manifest.Get("Spring-Boot-Lib")
 	if !ok {
 		return libcnb.BuildResult{}, fmt.Errorf("manifest does not contain Spring-Boot-Lib")
 	}
 
-	// gather libraries
-	d, err := libjvm.NewMavenJARListing(filepath.Join(context.Application.Path, lib))
+	// index libraries once for all analyzers
+	indexLayer, err := context.Layers.Layer(JarIndexLayer)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to read %s layer\n%w", JarIndexLayer, err)
+	}
+	index, err := NewApplicationJarIndex(context.Application.Path, lib, CachedJarIndexEntries(indexLayer))
 	if err != nil {
-		return libcnb.BuildResult{}, fmt.Errorf("unable to generate dependencies from %s\n%w", context.Application.Path, err)
+		return libcnb.BuildResult{}, fmt.Errorf("unable to index %s\n%w", lib, err)
 	}
+	if len(index.Jars) > 0 {
+		result.Layers = append(result.Layers, JarIndexCache{Index: index})
+	}
+
+	// the build policy applies to the dependencies as packaged, before development-only ones are removed
+	packaged := NewDependencies(index)
+
+	// strip development-only dependencies
+	if !cr.ResolveBool("BP_SPRING_BOOT_KEEP_DEV_DEPENDENCIES") {
+		removed, err := RemoveDevDependencies(context.Application.Path, lib, manifest)
+		if err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to remove development-only dependencies\n%w", err)
+		}
+		index = index.Without(removed)
+		if len(removed) > 0 {
+			b.Logger.Header("Removing development-only dependencies, set $BP_SPRING_BOOT_KEEP_DEV_DEPENDENCIES to keep them")
+			for _, r := range removed {
+				b.Logger.Bodyf("Removed %s", r)
+			}
+		}
+	}
+
+	if len(index.Jars) > 0 {
+		if err := WriteSBOM(context.Layers.LaunchSBOMPath, context.Application.Path, ApplicationSBOMComponents(index)); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to write application SBOM\n%w", err)
+		}
+	}
+
+	result.Labels, err = labels(context.Application.Path, manifest, provenance, index)
+	if err != nil {
+		return libcnb.BuildResult{}, err
+	}
+
+	// detect enterprise APIs from the namespace the Spring Boot generation does not use
+	policy, err := resolvePolicy(cr, "BP_SPRING_BOOT_MIXED_EE_NAMESPACES")
+	if err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	if err := ReportEENamespaces(b.Logger, version, index, policy); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+
+	// detect classes provided by more than one dependency
+	policy, err = resolvePolicy(cr, "BP_SPRING_BOOT_DUPLICATE_CLASSES")
+	if err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	allowed, _ := cr.Resolve("BP_SPRING_BOOT_DUPLICATE_CLASSES_ALLOWED")
+	if err := ReportDuplicateClasses(b.Logger, index, allowed, policy); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+
+	// detect dependencies under denied licenses
+	policy, err = resolvePolicy(cr, "BP_SPRING_BOOT_DENIED_LICENSES_POLICY")
+	if err != nil {
+		return libcnb.BuildResult{}, err
+	}
+	denied, _ := cr.Resolve("BP_SPRING_BOOT_DENIED_LICENSES")
+	if err := ReportDeniedLicenses(b.Logger, index, denied, policy); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+
+	// gather libraries
+	d := NewDependencies(index)
+
+	// match dependencies against an offline vulnerability database
+	dbPath, _ := cr.Resolve("BP_SPRING_BOOT_VULN_DB")
+	if db, ok, err := VulnerabilityDatabasePath(context.Platform.Bindings, context.Application.Path, dbPath); err != nil {
+		return libcnb.BuildResult{}, err
+	} else if ok {
+		policy, _ := cr.Resolve("BP_SPRING_BOOT_VULN_POLICY")
+		threshold, err := ParseSeverityPolicy("BP_SPRING_BOOT_VULN_POLICY", policy)
+		if err != nil {
+			return libcnb.BuildResult{}, err
+		}
+
+		report, err := ReportVulnerabilities(b.Logger, db, d, threshold)
+		if err != nil {
+			return libcnb.BuildResult{}, err
+		}
+		result.Layers = append(result.Layers, report)
+	}
+
+	javaTarget := 0
+	if classesDir != "" {
+		if javaTarget, err = MaxJavaVersionInDirectory(filepath.Join(context.Application.Path, classesDir)); err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to determine Java target of %s\n%w", classesDir, err)
+		}
+	}
+	result.Labels = append(result.Labels, SpringEcosystemLabels(d, javaTarget)...)
+
 	var additionalLibs []string
 	var classpathString string
+	if explodedFound {
+		if !thinFound {
+			b.Logger.Bodyf("Exploded application detected, inferred Spring Boot version %s from %s", version, lib)
+		}
+		classpathString = exploded.ClasspathString()
+	}
 
 	// Native Image
 	buildNativeImage := false
@@ -173,19 +309,43 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 		buildNativeImage = true
 	}
 
-	if buildNativeImage {
+	// check Java compatibility
+	jc, err := NewJavaCompatibilityValidator(filepath.Join(context.Buildpack.Path, "spring-boot-java-compatibility.toml"))
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to create Java compatibility validator\n%w", err)
+	}
+	jc.Logger = b.Logger
+	if s, ok := cr.Resolve("BP_SPRING_BOOT_JAVA_COMPATIBILITY"); ok {
+		fail := false
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "fail":
+			fail = true
+		case "warn":
+		default:
+			return libcnb.BuildResult{}, fmt.Errorf("invalid $BP_SPRING_BOOT_JAVA_COMPATIBILITY %s, must be fail or warn", s)
+		}
+		jc.Fail = &fail
+	}
 
-		javaMajorVersion, err := JavaMajorVersionFromJRE(b.Executor)
-		if err != nil {
+	javaMajorVersion, javaSource, jreVersion := 0, "", 0
+	if buildNativeImage || JREAvailable() {
+		if v, err := JavaMajorVersionFromJRE(b.Executor); err != nil {
 			b.Logger.Infof("Impossible to determine the Java version from the JRE, here is the error: %s", err)
 			b.Logger.Infof("Continuing with the build, considering the Java version unknown")
 		} else {
-			b.Logger.Infof("Java Major version from the JRE: %d", javaMajorVersion)
-
-			if versionRespectsConstraint(version, ">= 4.0.0") && javaMajorVersion < 25 {
-				return libcnb.BuildResult{}, fmt.Errorf("the Java version from the downloaded NIK/GraalVM is lower than 25 and the Spring Boot version is >= 4.0.0; this is not supported, more info at https://github.com/paketo-buildpacks/spring-boot/issues/562 - you can force the NIK JVM version using BP_JVM_VERSION=25, or with .sdkmanrc, etc")
-			}
+			b.Logger.Infof("Java Major version from the JRE: %d", v)
+			javaMajorVersion, javaSource, jreVersion = v, "the JRE", v
 		}
+	}
+	if v := jvmVersion(sherpa.GetEnvWithDefault("BP_JVM_VERSION", "")); javaMajorVersion == 0 && !buildNativeImage && v > 0 {
+		javaMajorVersion, javaSource = v, "$BP_JVM_VERSION"
+	}
+
+	if err := jc.Validate(version, javaMajorVersion, javaSource, buildNativeImage, performanceType); err != nil {
+		return libcnb.BuildResult{}, err
+	}
+
+	if buildNativeImage {
 
 		// Fix for https://github.com/paketo-buildpacks/spring-boot/issues/488
 		if versionRespectsConstraint(version, ">= 3.2.0") {
@@ -207,6 +367,13 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 
 	}
 
+	// verify bytecode against the JRE
+	if javaSource == "the JRE" {
+		if err := checkBytecodeTargets(context.Application.Path, classesDir, index, javaMajorVersion); err != nil {
+			return libcnb.BuildResult{}, err
+		}
+	}
+
 	// Spring Cloud Bindings
 	if scbJarFound := FindExistingDependency(d, "spring-cloud-bindings"); scbJarFound {
 		b.Logger.Header("A Spring Cloud Bindings library was found in the Spring Boot libs - not adding another one")
@@ -278,10 +445,12 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 
 		cdsLayer := NewSpringPerformance(dc, context.Application.Path, manifest, aotEnabled, performanceType, classpathString, reZipExplodedJar, cdsTrainingJavaToolOptions)
 		cdsLayer.Logger = b.Logger
+		cdsLayer.AOTCacheJava = jc.AOTCacheJava(version)
 		result.Layers = append(result.Layers, cdsLayer)
 
 	}
 
+	// add dependencies to BOM
 	result.BOM.Entries = append(result.BOM.Entries, libcnb.BOMEntry{
 		Name:     "dependencies",
 		Metadata: map[string]interface{}{"layer": "application", "dependencies": d},
@@ -298,21 +467,92 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 	if err := gv.Validate("spring-boot", version); err != nil {
 		return libcnb.BuildResult{}, fmt.Errorf("unable to validate spring-boot version\n%w", err)
 	}
+	for _, p := range SpringProjects {
+		if dep, ok := FindDependency(d, p.Group, p.Artifact); ok {
+			if err := gv.Validate(p.Slug, dep.Version); err != nil {
+				return libcnb.BuildResult{}, fmt.Errorf("unable to validate %s version\n%w", p.Slug, err)
+			}
+		}
+	}
 
 	// configure JVM for application type
 	classes, ok := manifest.Get("Spring-Boot-Classes")
 	if !ok {
 		return libcnb.BuildResult{}, fmt.Errorf("manifest does not contain Spring-Boot-Classes")
 	}
-	wr, err := NewWebApplicationResolver(classes, lib)
+	ac, err := NewApplicationConfiguration(filepath.Join(context.Application.Path, classes))
 	if err != nil {
-		return libcnb.BuildResult{}, fmt.Errorf("unable to create WebApplicationTypeResolver\n%w", err)
+		return libcnb.BuildResult{}, fmt.Errorf("unable to read application configuration\n%w", err)
 	}
-	at, err := NewWebApplicationType(context.Application.Path, wr)
+
+	// detect plaintext secrets in the packaged configuration
+	secretsPolicy, _ := cr.Resolve("BP_SPRING_BOOT_SECRETS_POLICY")
+	threshold, err := ParseSeverityPolicy("BP_SPRING_BOOT_SECRETS_POLICY", secretsPolicy)
 	if err != nil {
-		return libcnb.BuildResult{}, fmt.Errorf("unable to create WebApplicationType\n%w", err)
+		return libcnb.BuildResult{}, err
+	}
+	if err := ReportSecrets(b.Logger, context.Application.Path, classes, threshold); err != nil {
+		return libcnb.BuildResult{}, err
 	}
+
+	// validate the packaged configuration against the configuration metadata
+	if !cr.ResolveBool("BP_SPRING_BOOT_CONFIGURATION_VALIDATION_DISABLED") {
+		if err := ReportConfigurationProblems(b.Logger, context.Application.Path, classes, index); err != nil {
+			return libcnb.BuildResult{}, err
+		}
+	}
+
+	wr, err := NewWebApplicationResolverFromIndex(filepath.Join(context.Application.Path, classes), index)
+	if err != nil {
+		return libcnb.BuildResult{}, fmt.Errorf("unable to create WebApplicationTypeResolver\n%w", err)
+	}
+	wr.Configuration = ac
+	result.Labels = append(result.Labels, libcnb.Label{Key: LabelApplicationType, Value: wr.Resolve().String()})
+	if server, ok := wr.Server(); ok {
+		result.Labels = append(result.Labels, EmbeddedServerLabels(server, d)...)
+	}
+	workloads := wr.Workloads()
+	result.Labels = append(result.Labels, WorkloadsLabel(workloads))
+	actuator := Actuator{Configuration: ac, Dependencies: d, ApplicationType: wr.Resolve(), Version: version}
+	result.Labels = append(result.Labels, actuator.Labels()...)
+
+	// evaluate the build policy
+	policyPath, _ := cr.Resolve("BP_SPRING_BOOT_POLICY")
+	if buildPolicy, ok, err := ResolvePolicy(context.Platform.Bindings, context.Application.Path, policyPath); err != nil {
+		return libcnb.BuildResult{}, err
+	} else if ok {
+		violations := buildPolicy.Evaluate(PolicyFacts{BootVersion: version, Dependencies: packaged})
+		if err := ReportPolicyViolations(b.Logger, violations); err != nil {
+			return libcnb.BuildResult{}, err
+		}
+	}
+
+	at := NewWebApplicationType(index, wr)
 	at.Logger = b.Logger
+	at.JavaVersion = jreVersion
+	if at.JavaVersion == 0 {
+		// without a JRE, the application runs on at least the release it targets
+		at.JavaVersion = javaTarget
+		if v := jvmVersion(sherpa.GetEnvWithDefault("BP_JVM_VERSION", "")); v > at.JavaVersion {
+			at.JavaVersion = v
+		}
+	}
+	if performanceType != CdsAotCache {
+		// a training run measures the loaded classes instead
+		s, _ := cr.Resolve("BP_SPRING_BOOT_LOADED_CLASS_FACTOR")
+		factor, err := ParseLoadedClassFactor(s)
+		if err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to resolve $BP_SPRING_BOOT_LOADED_CLASS_FACTOR\n%w", err)
+		}
+
+		jvmClasses, ok, err := JVMClassCount()
+		if err != nil {
+			return libcnb.BuildResult{}, fmt.Errorf("unable to determine JVM class count\n%w", err)
+		}
+		if ok {
+			at.LoadedClassCount = LoadedClassCount(jvmClasses, len(wr.Classes), factor)
+		}
+	}
 	result.Layers = append(result.Layers, at)
 
 	if performanceType == Without {
@@ -322,6 +562,9 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 			if result, err = b.createSlices(context.Application.Path, index, result); err != nil {
 				return libcnb.BuildResult{}, fmt.Errorf("error creating slices\n%w", err)
 			}
+		} else if thinFound {
+			b.Logger.Header("Creating slice from resolved dependencies")
+			result.Slices = append(result.Slices, libcnb.Slice{Paths: []string{lib}})
 		}
 	}
 
@@ -329,9 +572,9 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 		result = b.contributeHelpers(context, result, helpers)
 	}
 
-	if bootJarFound || performanceType == CdsAotCache || performanceType == ExtractLayout {
+	if bootJarFound || explodedFound || performanceType == CdsAotCache || performanceType == ExtractLayout {
 		if mainClass != "" {
-			result.Processes = append(result.Processes, b.setProcessTypes(mainClass, classpathString)...)
+			result.Processes = append(result.Processes, b.setProcessTypes(mainClass, classpathString, DefaultProcessType(workloads))...)
 		} else {
 			return libcnb.BuildResult{}, fmt.Errorf("error finding Main-Class or Start-Class manifest entry for Process Type")
 		}
@@ -340,6 +583,19 @@ func (b Build) Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
 	return result, nil
 }
 
+func (b Build) resolveThinApplication(context libcnb.BuildContext, manifest *properties.Properties) (ExplodedApplication, error) {
+	repository, err := ThinRepository(context.Platform.Bindings, context.Application.Path)
+	if err != nil {
+		return ExplodedApplication{}, err
+	}
+
+	b.Logger.Headerf("Resolving Spring Boot Thin Launcher dependencies from %s", repository)
+	t := NewThinApplication(context.Application.Path, manifest, repository)
+	t.Logger = b.Logger
+
+	return t.Resolve()
+}
+
 func lookForServicesFileSystemProviderAndRemoveNested(context libcnb.BuildContext) error {
 	dir := filepath.Join(context.Application.Path, "META-INF", "services")
 	if servicesFolderExists, err := sherpa.DirExists(dir); err != nil {
@@ -377,7 +633,7 @@ func lookForServicesFileSystemProviderAndRemoveNested(context libcnb.BuildContex
 	return nil
 }
 
-func labels(jarPath string, manifest *properties.Properties) ([]libcnb.Label, error) {
+func labels(jarPath string, manifest *properties.Properties, provenance Provenance, index JarIndex) ([]libcnb.Label, error) {
 	var labels []libcnb.Label
 
 	if s, ok := manifest.Get("Spring-Boot-Version"); ok {
@@ -392,7 +648,13 @@ func labels(jarPath string, manifest *properties.Properties) ([]libcnb.Label, er
 		labels = append(labels, libcnb.Label{Key: LabelImageVersion, Value: s})
 	}
 
-	mdLabels, err := configurationMetadataLabels(jarPath, manifest)
+	pLabels, err := provenance.Labels()
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate provenance labels\n%w", err)
+	}
+	labels = append(labels, pLabels...)
+
+	mdLabels, err := configurationMetadataLabels(jarPath, index)
 	if err != nil {
 		return nil, fmt.Errorf("unable to generate data flow configuration metadata\n%w", err)
 	}
@@ -401,7 +663,7 @@ func labels(jarPath string, manifest *properties.Properties) ([]libcnb.Label, er
 	return labels, nil
 }
 
-func configurationMetadataLabels(appDir string, manifest *properties.Properties) ([]libcnb.Label, error) {
+func configurationMetadataLabels(appDir string, index JarIndex) ([]libcnb.Label, error) {
 	if ok, err := DataFlowConfigurationExists(appDir); !ok || err != nil {
 		return []libcnb.Label{}, err
 	}
@@ -412,26 +674,14 @@ func configurationMetadataLabels(appDir string, manifest *properties.Properties)
 		return nil, fmt.Errorf("unable to read configuration metadata from %s\n%w", appDir, err)
 	}
 
-	lib, ok := manifest.Get("Spring-Boot-Lib")
-	if !ok {
-		return nil, errors.New("manifest does not contain Spring-Boot-Lib")
-	}
-	file := filepath.Join(lib, "*.jar")
-	files, err := filepath.Glob(file)
+	jarMD, err := index.ConfigurationMetadata()
 	if err != nil {
-		return nil, fmt.Errorf("unable to glob %s\n%w", file, err)
+		return nil, fmt.Errorf("unable to read configuration metadata\n%w", err)
 	}
+	md.Groups = append(md.Groups, jarMD.Groups...)
+	md.Properties = append(md.Properties, jarMD.Properties...)
+	md.Hints = append(md.Hints, jarMD.Hints...)
 
-	for _, file := range files {
-		jarMD, err := NewConfigurationMetadataFromJAR(file)
-		if err != nil {
-			return nil, fmt.Errorf("unable to read configuration metadata from %s\n%w", file, err)
-		}
-
-		md.Groups = append(md.Groups, jarMD.Groups...)
-		md.Properties = append(md.Properties, jarMD.Properties...)
-		md.Hints = append(md.Hints, jarMD.Hints...)
-	}
 	if len(md.Groups) > 0 || len(md.Properties) > 0 || len(md.Hints) > 0 {
 		b := &bytes.Buffer{}
 		if err := json.NewEncoder(b).Encode(md); err != nil {
@@ -502,7 +752,7 @@ func friendlySize(size float64) string {
 	return fmt.Sprintf("%0.1f %s", size, unit)
 }
 
-func FindExistingDependency(jars []libjvm.MavenJAR, dependencyName string) bool {
+func FindExistingDependency(jars []Dependency, dependencyName string) bool {
 	for _, lib := range jars {
 		if lib.Name == dependencyName {
 			return true
@@ -567,7 +817,7 @@ func (b *Build) contributeHelpers(context libcnb.BuildContext, result libcnb.Bui
 	return result
 }
 
-func (b *Build) setProcessTypes(mainClass string, classpathString string) []libcnb.Process {
+func (b *Build) setProcessTypes(mainClass string, classpathString string, defaultType string) []libcnb.Process {
 
 	command := "java"
 	arguments := []string{}
@@ -596,8 +846,17 @@ func (b *Build) setProcessTypes(mainClass string, classpathString string) []libc
 			Command:   command,
 			Arguments: arguments,
 			Direct:    true,
+			Default:   defaultType == "web",
+		})
+	if defaultType == "worker" {
+		processes = append(processes, libcnb.Process{
+			Type:      "worker",
+			Command:   command,
+			Arguments: arguments,
+			Direct:    true,
 			Default:   true,
 		})
+	}
 	return processes
 }
 
@@ -664,6 +923,17 @@ func (b *Build) findSpringBootExecutableJAR(appPath string) (string, *properties
 	return jarPath, props, nil
 }
 
+// jvmVersion returns the Java release requested by a BP_JVM_VERSION value such as 21, 21.* or 1.8, and 0 if it
+// cannot be determined.
+func jvmVersion(version string) int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "1.")
+	v, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
 func bootCDSExtractionSupported(manifestVer string) bool {
 	return versionRespectsConstraint(manifestVer, ">= 3.3.0")
 }
@@ -679,3 +949,48 @@ func versionRespectsConstraint(manifestVer string, constraint string) bool {
 	}
 	return false
 }
+
+func checkBytecodeTargets(appPath string, classes string, index JarIndex, jre int) error {
+	if classes != "" {
+		classes = filepath.Join(appPath, classes)
+	}
+
+	targets, err := UnsupportedBytecodeTargets(classes, index, jre)
+	if err != nil {
+		return fmt.Errorf("unable to determine Java release targeted by the application\n%w", err)
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	max := 0
+	s := make([]string, len(targets))
+	for i, t := range targets {
+		if filepath.IsAbs(t.Path) {
+			if rel, err := filepath.Rel(appPath, t.Path); err == nil {
+				t.Path = rel
+			}
+		}
+		s[i] = t.String()
+		if t.Java > max {
+			max = t.Java
+		}
+	}
+
+	return fmt.Errorf("the application targets a newer Java release than the JRE %d, select a JRE with BP_JVM_VERSION=%d or later\n  %s",
+		jre, max, strings.Join(s, "\n  "))
+}
+
+// resolvePolicy returns whether a finding configured by name fails the build or logs a warning, warn unless configured.
+func resolvePolicy(cr libpak.ConfigurationResolver, name string) (string, error) {
+	s, _ := cr.Resolve(name)
+
+	switch policy := strings.ToLower(strings.TrimSpace(s)); policy {
+	case "":
+		return "warn", nil
+	case "warn", "fail":
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid $%s %s, must be fail or warn", name, s)
+	}
+}
@@ -17,6 +17,7 @@
 package boot_test
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
@@ -26,6 +27,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/paketo-buildpacks/libjvm"
 	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/bard"
 	"github.com/paketo-buildpacks/libpak/effect"
 	"github.com/paketo-buildpacks/libpak/effect/mocks"
 	"github.com/paketo-buildpacks/spring-boot/v5/boot"
@@ -176,6 +178,62 @@ Implementation-Version: 2.2.2
 		}))
 	})
 
+	it("contributes provenance labels", func() {
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Spring-Boot-Version: 1.1.1
+Spring-Boot-Classes: BOOT-INF/classes
+Spring-Boot-Lib: BOOT-INF/lib
+`), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes", "git.properties"),
+			[]byte("git.commit.id=test-revision"), 0644)).To(Succeed())
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Labels).To(ContainElement(libcnb.Label{Key: "org.opencontainers.image.revision", Value: "test-revision"}))
+	})
+
+	it("does not contribute git provenance labels when disabled", func() {
+		t.Setenv("BP_SPRING_BOOT_GIT_LABELS_DISABLED", "true")
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Spring-Boot-Version: 1.1.1
+Spring-Boot-Classes: BOOT-INF/classes
+Spring-Boot-Lib: BOOT-INF/lib
+`), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes", "git.properties"),
+			[]byte("git.commit.id=test-revision"), 0644)).To(Succeed())
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Labels).NotTo(ContainElement(libcnb.Label{Key: "org.opencontainers.image.revision", Value: "test-revision"}))
+	})
+
+	it("contributes Spring ecosystem labels", func() {
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Spring-Boot-Version: 1.1.1
+Spring-Boot-Classes: BOOT-INF/classes
+Spring-Boot-Lib: BOOT-INF/lib
+`), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes", "Application.class"), classFile(61), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib"), 0755)).To(Succeed())
+		in, err := os.ReadFile(filepath.Join("testdata", "stub-empty.jar"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "spring-core-6.1.2.jar"), in, 0644)).To(Succeed())
+
+		result, err := build.Build(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Labels).To(ContainElements(
+			libcnb.Label{Key: "org.springframework.version", Value: "6.1.2"},
+			libcnb.Label{Key: "org.springframework.boot.java.target", Value: "17"},
+			libcnb.Label{Key: "org.springframework.boot.application-type", Value: "none"},
+		))
+	})
+
 	it("contributes dependencies bom entry for API <= 0.6", func() {
 		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
 Spring-Boot-Version: 1.1.1
@@ -183,8 +241,9 @@ Spring-Boot-Classes: BOOT-INF/classes
 Spring-Boot-Lib: BOOT-INF/lib
 `), 0644)).To(Succeed())
 		Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib"), 0755)).To(Succeed())
-		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "test-file-2.2.2.jar"),
-			[]byte{}, 0644)).To(Succeed())
+		in, err := os.ReadFile(filepath.Join("testdata", "stub-empty.jar"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "test-file-2.2.2.jar"), in, 0644)).To(Succeed())
 		ctx.Buildpack.API = "0.6"
 
 		result, err := build.Build(ctx)
@@ -194,11 +253,11 @@ Spring-Boot-Lib: BOOT-INF/lib
 			Name: "dependencies",
 			Metadata: map[string]interface{}{
 				"layer": "application",
-				"dependencies": []libjvm.MavenJAR{
+				"dependencies": []boot.Dependency{
 					{
 						Name:    "test-file",
 						Version: "2.2.2",
-						SHA256:  "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						SHA256:  "58783449d4648c437a793f262f313d79c8499be1906bbeed2834e60b2187fec2",
 					},
 				},
 			},
@@ -503,20 +562,20 @@ Spring-Boot-Lib: BOOT-INF/lib
 
 	context("have an existing spring-cloud-bindings jar among spring libs", func() {
 
-		mavenJars := make([]libjvm.MavenJAR, 0, 2)
-		mavenJars = append(mavenJars, libjvm.MavenJAR{
+		mavenJars := make([]boot.Dependency, 0, 2)
+		mavenJars = append(mavenJars, boot.Dependency{
 			Name:    "spring-boot",
 			Version: "3",
 			SHA256:  "1",
 		})
-		mavenJars = append(mavenJars, libjvm.MavenJAR{
+		mavenJars = append(mavenJars, boot.Dependency{
 			Name:    "junit",
 			Version: "5",
 			SHA256:  "2",
 		})
 
 		it("returns the version of the found spring cloud bindings jar", func() {
-			expectedScbJar := libjvm.MavenJAR{
+			expectedScbJar := boot.Dependency{
 				Name:    "spring-cloud-bindings",
 				Version: "1.8.1",
 				SHA256:  "79a036f93414230a402d30d75ab2ccec9a953259bdeb3dd31e8fee2056445df3",
@@ -524,7 +583,7 @@ Spring-Boot-Lib: BOOT-INF/lib
 			mavenJars = append(mavenJars, expectedScbJar)
 
 			// add another jar to make sure the detection stops correctly after the first found occurence
-			mavenJars = append(mavenJars, libjvm.MavenJAR{
+			mavenJars = append(mavenJars, boot.Dependency{
 				Name:    "tomcat",
 				Version: "10",
 				SHA256:  "3",
@@ -567,8 +626,9 @@ Spring-Boot-Lib: BOOT-INF/lib
 			result, err := build.Build(ctx)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(result.Layers).To(HaveLen(1))
-			Expect(result.Layers[0].Name()).To(Equal("web-application-type"))
+			Expect(result.Layers).To(HaveLen(2))
+			Expect(result.Layers[0].Name()).To(Equal("jar-index"))
+			Expect(result.Layers[1].Name()).To(Equal("web-application-type"))
 
 			Expect(result.BOM.Entries).To(HaveLen(1))
 			Expect(result.BOM.Entries[0].Name).To(Equal("dependencies"))
@@ -589,8 +649,9 @@ Spring-Boot-Lib: BOOT-INF/lib
 			result, err := build.Build(ctx)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(result.Layers).To(HaveLen(1))
-			Expect(result.Layers[0].Name()).To(Equal("web-application-type"))
+			Expect(result.Layers).To(HaveLen(2))
+			Expect(result.Layers[0].Name()).To(Equal("jar-index"))
+			Expect(result.Layers[1].Name()).To(Equal("web-application-type"))
 
 			Expect(result.BOM.Entries).To(HaveLen(1))
 			Expect(result.BOM.Entries[0].Name).To(Equal("dependencies"))
@@ -849,6 +910,520 @@ Spring-Boot-Lib: BOOT-INF/lib
 		})
 	})
 
+	context("when the application is an exploded classpath layout", func() {
+
+		it.Before(func() {
+			t.Setenv("BP_SPRING_CLOUD_BINDINGS_DISABLED", "true")
+			Expect(os.RemoveAll(filepath.Join(ctx.Application.Path, "META-INF"))).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "lib"), 0755)).To(Succeed())
+
+			in, err := os.ReadFile(filepath.Join("testdata", "stub-empty.jar"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "lib", "spring-boot-3.2.1.jar"), in, 0644)).To(Succeed())
+		})
+
+		it("contributes labels, layers and processes", func() {
+			t.Setenv("BP_SPRING_BOOT_MAIN_CLASS", "com.example.DemoApplication")
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Labels).To(ContainElement(libcnb.Label{Key: "org.springframework.boot.version", Value: "3.2.1"}))
+			Expect(result.Layers).To(HaveLen(2))
+			Expect(result.Layers[0].(boot.JarIndexCache).Index.Jars).To(HaveLen(1))
+			Expect(result.Layers[0].(boot.JarIndexCache).Index.Jars[0].Path).To(Equal(filepath.Join("lib", "spring-boot-3.2.1.jar")))
+			Expect(result.Layers[1].Name()).To(Equal("web-application-type"))
+			Expect(result.Processes).To(ContainElement(libcnb.Process{
+				Type:      "web",
+				Command:   "java",
+				Arguments: []string{"-cp", "lib/*", "com.example.DemoApplication"},
+				Direct:    true,
+				Default:   true,
+			}))
+		})
+
+		it("contributes a default worker process for applications that only consume messages", func() {
+			t.Setenv("BP_SPRING_BOOT_MAIN_CLASS", "com.example.DemoApplication")
+			writeJar(t, filepath.Join(ctx.Application.Path, "lib", "spring-kafka-3.2.0.jar"), map[string]string{
+				"org/springframework/kafka/annotation/KafkaListener.class": "",
+			})
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			arguments := []string{"-cp", "lib/*", "com.example.DemoApplication"}
+			Expect(result.Labels).To(ContainElement(libcnb.Label{Key: "org.springframework.boot.workloads", Value: "messaging"}))
+			Expect(result.Processes).To(Equal([]libcnb.Process{
+				{Type: "spring-boot-app", Command: "java", Arguments: arguments, Direct: true},
+				{Type: "task", Command: "java", Arguments: arguments, Direct: true},
+				{Type: "web", Command: "java", Arguments: arguments, Direct: true},
+				{Type: "worker", Command: "java", Arguments: arguments, Direct: true, Default: true},
+			}))
+		})
+
+		it("reads the main class from the start script", func() {
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "bin"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "classes"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "bin", "demo"), []byte(`#!/bin/sh
+MAIN_CLASS=com.example.ScriptApplication
+`), 0755)).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Processes).To(ContainElement(libcnb.Process{
+				Type:      "web",
+				Command:   "java",
+				Arguments: []string{"-cp", "classes:lib/*", "com.example.ScriptApplication"},
+				Direct:    true,
+				Default:   true,
+			}))
+		})
+
+		it("fails without a main class", func() {
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("BP_SPRING_BOOT_MAIN_CLASS")))
+		})
+	})
+
+	context("when the application uses the Spring Boot Thin Launcher", func() {
+		var repository string
+
+		it.Before(func() {
+			var err error
+
+			repository, err = os.MkdirTemp("", "build-repository")
+			Expect(err).NotTo(HaveOccurred())
+
+			t.Setenv("BP_SPRING_CLOUD_BINDINGS_DISABLED", "true")
+			t.Setenv("BP_SPRING_BOOT_THIN_REPOSITORY", repository)
+
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Main-Class: org.springframework.boot.loader.wrapper.ThinJarWrapper
+Start-Class: com.example.DemoApplication
+`), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "thin.properties"), []byte(`computed=true
+dependencies.spring-boot=org.springframework.boot:spring-boot:3.2.1
+`), 0644)).To(Succeed())
+
+			in, err := os.ReadFile(filepath.Join("testdata", "stub-empty.jar"))
+			Expect(err).NotTo(HaveOccurred())
+			file := filepath.Join(repository, "org", "springframework", "boot", "spring-boot", "3.2.1", "spring-boot-3.2.1.jar")
+			Expect(os.MkdirAll(filepath.Dir(file), 0755)).To(Succeed())
+			Expect(os.WriteFile(file, in, 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(repository)).To(Succeed())
+		})
+
+		it("resolves dependencies and contributes slices and processes", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(ctx.Application.Path, "lib", "spring-boot-3.2.1.jar")).To(BeARegularFile())
+			Expect(result.Labels).To(ContainElement(libcnb.Label{Key: "org.springframework.boot.version", Value: "3.2.1"}))
+			Expect(result.Slices).To(ContainElement(libcnb.Slice{Paths: []string{"lib"}}))
+			Expect(result.BOM.Entries[0].Name).To(Equal("dependencies"))
+			Expect(result.Processes).To(ContainElement(libcnb.Process{
+				Type:      "web",
+				Command:   "java",
+				Arguments: []string{"-cp", ".:lib/*", "com.example.DemoApplication"},
+				Direct:    true,
+				Default:   true,
+			}))
+		})
+	})
+
+	context("Spring project generations", func() {
+		it.Before(func() {
+			writeBootApplication(t, ctx.Application.Path, map[string]map[string]string{
+				"core.jar": {
+					"META-INF/maven/org.springframework/spring-core/pom.properties": "groupId=org.springframework\nartifactId=spring-core\nversion=5.1.20.RELEASE\n",
+				},
+			})
+
+			var err error
+			ctx.Buildpack.Path, err = filepath.Abs("..")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("warns about projects identified by their Maven coordinates whose support ended", func() {
+			out := &bytes.Buffer{}
+			b := boot.Build{Logger: bard.NewLogger(out)}
+
+			_, err := b.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(out.String()).To(ContainSubstring("This application uses Spring Framework 5.1.20.RELEASE. Open Source updates for 5.1.x ended on 2020-12-31."))
+		})
+	})
+
+	context("enterprise API namespaces", func() {
+		it.Before(func() {
+			writeBootApplication(t, ctx.Application.Path, map[string]map[string]string{
+				"legacy-1.0.0.jar": {"com/example/Filter.class": "\xca\xfe\xba\xbe Ljavax/servlet/Filter;"},
+			})
+		})
+
+		it("fails with javax dependencies in a Spring Boot 3 application", func() {
+			t.Setenv("BP_SPRING_BOOT_MIXED_EE_NAMESPACES", "fail")
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("Spring Boot 3.3.1 uses the jakarta namespace for enterprise APIs")))
+			Expect(err).To(MatchError(ContainSubstring("legacy-1.0.0.jar uses javax.servlet")))
+		})
+
+		it("warns by default", func() {
+			out := &bytes.Buffer{}
+			b := boot.Build{Logger: bard.NewLogger(out)}
+
+			_, err := b.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(out.String()).To(ContainSubstring("legacy-1.0.0.jar uses javax.servlet"))
+		})
+	})
+
+	context("duplicate classes", func() {
+		it.Before(func() {
+			writeBootApplication(t, ctx.Application.Path, map[string]map[string]string{
+				"demo-1.0.0.jar": {"com/example/Demo.class": ""},
+				"demo-2.0.0.jar": {"com/example/Demo.class": ""},
+			})
+		})
+
+		it("warns by default", func() {
+			out := &bytes.Buffer{}
+			b := boot.Build{Logger: bard.NewLogger(out)}
+
+			_, err := b.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(out.String()).To(ContainSubstring("demo-1.0.0.jar and demo-2.0.0.jar: 1 classes, such as com.example.Demo"))
+		})
+
+		it("fails with classes provided by more than one dependency", func() {
+			t.Setenv("BP_SPRING_BOOT_DUPLICATE_CLASSES", "fail")
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("demo-1.0.0.jar and demo-2.0.0.jar: 1 classes, such as com.example.Demo")))
+		})
+
+		it("ignores allowed overlaps", func() {
+			t.Setenv("BP_SPRING_BOOT_DUPLICATE_CLASSES", "fail")
+			t.Setenv("BP_SPRING_BOOT_DUPLICATE_CLASSES_ALLOWED", "demo-1.*.jar")
+
+			_, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	context("application SBOM", func() {
+		it.Before(func() {
+			writeBootApplication(t, ctx.Application.Path, map[string]map[string]string{
+				"demo-1.0.0.jar": {"META-INF/maven/com.example/demo/pom.properties": "groupId=com.example\nartifactId=demo\nversion=1.0.0\n"},
+			})
+		})
+
+		it("writes the application SBOM", func() {
+			_, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ctx.Layers.LaunchSBOMPath(libcnb.CycloneDXJSON)).To(BeARegularFile())
+			Expect(ctx.Layers.LaunchSBOMPath(libcnb.SyftJSON)).To(BeARegularFile())
+		})
+	})
+
+	context("denied licenses", func() {
+		it.Before(func() {
+			writeBootApplication(t, ctx.Application.Path, map[string]map[string]string{
+				"demo-1.0.0.jar": {"META-INF/LICENSE": "GNU AFFERO GENERAL PUBLIC LICENSE\nVersion 3, 19 November 2007"},
+			})
+			t.Setenv("BP_SPRING_BOOT_DENIED_LICENSES", "AGPL-3.0, SSPL-1.0")
+		})
+
+		it("warns about dependencies under denied licenses", func() {
+			out := &bytes.Buffer{}
+			b := boot.Build{Logger: bard.NewLogger(out)}
+
+			result, err := b.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(out.String()).To(ContainSubstring("demo-1.0.0.jar is licensed under AGPL-3.0-only"))
+			for _, e := range result.BOM.Entries {
+				if e.Name == "dependencies" {
+					Expect(e.Metadata["dependencies"].([]boot.Dependency)[0].Licenses).To(Equal([]string{"AGPL-3.0-only"}))
+				}
+			}
+		})
+
+		it("fails with the fail policy", func() {
+			t.Setenv("BP_SPRING_BOOT_DENIED_LICENSES_POLICY", "fail")
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("Dependencies are licensed under licenses denied by $BP_SPRING_BOOT_DENIED_LICENSES:\n  demo-1.0.0.jar is licensed under AGPL-3.0-only")))
+		})
+	})
+
+	context("development-only dependencies", func() {
+		it.Before(func() {
+			writeBootApplication(t, ctx.Application.Path, map[string]map[string]string{
+				"spring-boot-devtools-3.3.1.jar": {"org/springframework/boot/devtools/RemoteSpringApplication.class": ""},
+				"spring-core-6.1.10.jar":         {"org/springframework/core/SpringVersion.class": ""},
+			}, "Spring-Boot-Classpath-Index: BOOT-INF/classpath.idx")
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classpath.idx"), []byte(`- "BOOT-INF/lib/spring-boot-devtools-3.3.1.jar"
+- "BOOT-INF/lib/spring-core-6.1.10.jar"
+`), 0644)).To(Succeed())
+		})
+
+		it("removes development-only dependencies", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "spring-boot-devtools-3.3.1.jar")).NotTo(BeAnExistingFile())
+			Expect(os.ReadFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classpath.idx"))).To(Equal([]byte(`- "BOOT-INF/lib/spring-core-6.1.10.jar"
+`)))
+			for _, e := range result.BOM.Entries {
+				if e.Name == "dependencies" {
+					Expect(e.Metadata["dependencies"]).To(HaveLen(1))
+				}
+			}
+		})
+
+		it("keeps development-only dependencies with $BP_SPRING_BOOT_KEEP_DEV_DEPENDENCIES", func() {
+			t.Setenv("BP_SPRING_BOOT_KEEP_DEV_DEPENDENCIES", "true")
+
+			_, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "spring-boot-devtools-3.3.1.jar")).To(BeARegularFile())
+		})
+	})
+
+	context("secrets in the packaged configuration", func() {
+		it.Before(func() {
+			writeBootApplication(t, ctx.Application.Path, nil)
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes", "application.properties"),
+				[]byte("spring.datasource.password=s3cr3t-value\n"), 0644)).To(Succeed())
+		})
+
+		it("warns by default", func() {
+			out := &bytes.Buffer{}
+			b := boot.Build{Logger: bard.NewLogger(out)}
+
+			_, err := b.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(out.String()).To(ContainSubstring("BOOT-INF/classes/application.properties: spring.datasource.password (literal value, high)"))
+			Expect(out.String()).NotTo(ContainSubstring("s3cr3t-value"))
+		})
+
+		it("fails according to the policy", func() {
+			t.Setenv("BP_SPRING_BOOT_SECRETS_POLICY", "fail-on=high")
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("the packaged configuration contains secrets of severity high or higher")))
+		})
+	})
+
+	context("configuration metadata", func() {
+		it.Before(func() {
+			writeBootApplication(t, ctx.Application.Path, map[string]map[string]string{
+				"spring-boot-autoconfigure-3.3.1.jar": {"META-INF/spring-configuration-metadata.json": `{
+  "groups": [{"name": "spring.datasource"}],
+  "properties": [
+    {"name": "spring.datasource.url", "type": "java.lang.String"},
+    {"name": "spring.datasource.initialization-mode", "deprecation": {"level": "error", "replacement": "spring.sql.init.mode"}}
+  ]
+}`},
+			})
+		})
+
+		it("warns about unknown properties", func() {
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes", "application.properties"),
+				[]byte("spring.datasource.ulr=jdbc:h2:mem:test\n"), 0644)).To(Succeed())
+			out := &bytes.Buffer{}
+			b := boot.Build{Logger: bard.NewLogger(out)}
+
+			_, err := b.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(out.String()).To(ContainSubstring("BOOT-INF/classes/application.properties: spring.datasource.ulr is not a known property"))
+		})
+
+		it("fails with properties that are no longer supported", func() {
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes", "application.properties"),
+				[]byte("spring.datasource.initialization-mode=always\n"), 0644)).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("spring.datasource.initialization-mode is no longer supported, use spring.sql.init.mode instead")))
+		})
+
+		it("skips validation with $BP_SPRING_BOOT_CONFIGURATION_VALIDATION_DISABLED", func() {
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes", "application.properties"),
+				[]byte("spring.datasource.initialization-mode=always\n"), 0644)).To(Succeed())
+			t.Setenv("BP_SPRING_BOOT_CONFIGURATION_VALIDATION_DISABLED", "true")
+
+			_, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	context("build policy", func() {
+		it.Before(func() {
+			writeBootApplication(t, ctx.Application.Path, map[string]map[string]string{
+				"log4j-core-2.14.1.jar": {
+					"META-INF/maven/org.apache.logging.log4j/log4j-core/pom.properties": "groupId=org.apache.logging.log4j\nartifactId=log4j-core\nversion=2.14.1\n",
+				},
+			})
+			t.Setenv("BP_SPRING_BOOT_POLICY", "policy.toml")
+		})
+
+		it("fails on error-level violations", func() {
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "policy.toml"), []byte(`
+[[rules]]
+type = "forbidden-artifact"
+artifact = "org.apache.logging.log4j:log4j-core"
+versions = "< 2.17.1"
+`), 0644)).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("[ERROR] forbidden-artifact org.apache.logging.log4j:log4j-core < 2.17.1: org.apache.logging.log4j:log4j-core:2.14.1 is forbidden")))
+		})
+
+		it("passes with warning-level violations", func() {
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "policy.toml"), []byte(`
+[[rules]]
+type = "min-boot-version"
+version = "3.4.0"
+severity = "warning"
+`), 0644)).To(Succeed())
+			out := &bytes.Buffer{}
+			b := boot.Build{Logger: bard.NewLogger(out)}
+
+			_, err := b.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(out.String()).To(ContainSubstring("[WARNING] min-boot-version"))
+		})
+
+		it("evaluates development-only dependencies before they are removed", func() {
+			writeBootApplication(t, ctx.Application.Path, map[string]map[string]string{
+				"spring-boot-devtools-3.3.1.jar": {
+					"META-INF/maven/org.springframework.boot/spring-boot-devtools/pom.properties": "groupId=org.springframework.boot\nartifactId=spring-boot-devtools\nversion=3.3.1\n",
+				},
+			})
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "policy.toml"), []byte(`
+[[rules]]
+type = "forbidden-artifact"
+artifact = "org.springframework.boot:spring-boot-devtools"
+`), 0644)).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("org.springframework.boot:spring-boot-devtools:3.3.1 is forbidden")))
+			Expect(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "spring-boot-devtools-3.3.1.jar")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	context("vulnerability database", func() {
+		it.Before(func() {
+			writeBootApplication(t, ctx.Application.Path, map[string]map[string]string{
+				"spring-beans-5.3.17.jar": {
+					"META-INF/maven/org.springframework/spring-beans/pom.properties": "groupId=org.springframework\nartifactId=spring-beans\nversion=5.3.17\n",
+				},
+			})
+
+			db := t.TempDir()
+			Expect(os.WriteFile(filepath.Join(db, "GHSA-36p3-wjmg-h94x.json"), []byte(springShellAdvisory), 0644)).To(Succeed())
+			ctx.Platform.Bindings = libcnb.Bindings{libcnb.NewBinding("osv", db, map[string]string{"type": "vulnerability-db"})}
+		})
+
+		it("reports vulnerable dependencies", func() {
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			var report boot.VulnerabilityReport
+			for _, l := range result.Layers {
+				if r, ok := l.(boot.VulnerabilityReport); ok {
+					report = r
+				}
+			}
+			Expect(report.Findings).To(HaveLen(1))
+			Expect(report.Findings[0].String()).To(Equal("org.springframework:spring-beans:5.3.17: CVE-2022-22965 (critical)"))
+		})
+
+		it("fails according to the policy", func() {
+			t.Setenv("BP_SPRING_BOOT_VULN_POLICY", "fail-on=critical")
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("dependencies have vulnerabilities of severity critical or higher:\n  org.springframework:spring-beans:5.3.17")))
+		})
+	})
+
+	context("when a JRE is available at build time", func() {
+		var jreHome string
+
+		it.Before(func() {
+			jreHome = t.TempDir()
+			Expect(os.MkdirAll(filepath.Join(jreHome, "bin"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(jreHome, "bin", "java"), []byte{}, 0755)).To(Succeed())
+			t.Setenv("JRE_HOME", jreHome)
+
+			executor = &mocks.Executor{}
+			executor.On("Execute", mock.Anything).Run(func(args mock.Arguments) {
+				execution := args.Get(0).(effect.Execution)
+				if execution.Stderr != nil {
+					_, err := io.WriteString(execution.Stderr, `openjdk version "17.0.12" 2024-07-16 LTS`)
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}).Return(nil)
+			build.Executor = executor
+
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Spring-Boot-Version: 2.7.1
+Spring-Boot-Classes: BOOT-INF/classes
+Spring-Boot-Lib: BOOT-INF/lib
+Start-Class: test-start-class
+`), 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(ctx.Application.Path, "BOOT-INF", "lib"), 0755)).To(Succeed())
+		})
+
+		it("fails when a dependency targets a newer Java release", func() {
+			writeJar(t, filepath.Join(ctx.Application.Path, "BOOT-INF", "lib", "test.jar"), map[string]string{
+				"com/example/A.class": string(classFile(65)),
+			})
+
+			_, err := build.Build(ctx)
+			Expect(err).To(MatchError(ContainSubstring("the application targets a newer Java release than the JRE 17")))
+			Expect(err).To(MatchError(ContainSubstring("BOOT-INF/lib/test.jar (com.example.A) targets Java 21")))
+		})
+
+		it("succeeds when the application targets the JRE", func() {
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes", "A.class"), classFile(61), 0644)).To(Succeed())
+
+			_, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("sizes the web application for the Java release of the JRE", func() {
+			t.Setenv("BP_JVM_VERSION", "21")
+			Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "BOOT-INF", "classes", "A.class"), classFile(61), 0644)).To(Succeed())
+
+			result, err := build.Build(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			var javaVersion int
+			for _, l := range result.Layers {
+				if w, ok := l.(boot.WebApplicationType); ok {
+					javaVersion = w.JavaVersion
+				}
+			}
+			Expect(javaVersion).To(Equal(17))
+		})
+	})
+
 	context("when we build a native app. with Spring Boot 4", func() {
 
 		it.Before(func() {
@@ -857,6 +1432,11 @@ Spring-Boot-Lib: BOOT-INF/lib
 				Metadata: map[string]interface{}{"native-image": true},
 			})
 			executor = &mocks.Executor{}
+
+			// the Java compatibility table of the buildpack
+			var err error
+			ctx.Buildpack.Path, err = filepath.Abs("..")
+			Expect(err).NotTo(HaveOccurred())
 		})
 
 		it("fails with a NIK JVM version < 25", func() {
@@ -884,7 +1464,7 @@ Spring-Boot-Lib: BOOT-INF/lib
 
 			result, err := build.Build(ctx)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal("the Java version from the downloaded NIK/GraalVM is lower than 25 and the Spring Boot version is >= 4.0.0; this is not supported, more info at https://github.com/paketo-buildpacks/spring-boot/issues/562 - you can force the NIK JVM version using BP_JVM_VERSION=25, or with .sdkmanrc, etc"))
+			Expect(err.Error()).To(Equal("the Java version 21 from the JRE is not supported: a native image of Spring Boot 4.0.0 needs Java 25 or later; set BP_JVM_VERSION=25 to select a supported Java version"))
 			Expect(result.Layers).To(HaveLen(0))
 
 			Expect(os.Unsetenv("JRE_HOME")).To(Succeed())
@@ -923,3 +1503,26 @@ Spring-Boot-Lib: BOOT-INF/lib
 	})
 
 }
+
+// writeBootApplication lays out a Spring Boot 3.3.1 executable jar at path, with jars of the given entries in
+// BOOT-INF/lib and additional manifest attributes, and disables Spring Cloud Bindings.
+func writeBootApplication(t *testing.T, path string, jars map[string]map[string]string, attributes ...string) {
+	t.Helper()
+	Expect := NewWithT(t).Expect
+
+	manifest := "Spring-Boot-Version: 3.3.1\nSpring-Boot-Classes: BOOT-INF/classes\nSpring-Boot-Lib: BOOT-INF/lib\nStart-Class: test-start-class\n"
+	for _, a := range attributes {
+		manifest += a + "\n"
+	}
+	Expect(os.WriteFile(filepath.Join(path, "META-INF", "MANIFEST.MF"), []byte(manifest), 0644)).To(Succeed())
+
+	for _, dir := range []string{"classes", "lib"} {
+		Expect(os.MkdirAll(filepath.Join(path, "BOOT-INF", dir), 0755)).To(Succeed())
+	}
+
+	for name, entries := range jars {
+		writeJar(t, filepath.Join(path, "BOOT-INF", "lib", name), entries)
+	}
+
+	t.Setenv("BP_SPRING_CLOUD_BINDINGS_DISABLED", "true")
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/paketo-buildpacks/libjvm/count"
+)
+
+// DefaultLoadedClassFactor is the share of available classes the memory calculator expects an application to load.
+const DefaultLoadedClassFactor = 0.35
+
+// JVMClassCount returns the number of classes in the JVM, from $BPI_JVM_CLASS_COUNT when the JVM provider exposes it at
+// build time, or by counting the classes of the JVM in $JAVA_HOME.
+func JVMClassCount() (int, bool, error) {
+	if s, ok := os.LookupEnv("BPI_JVM_CLASS_COUNT"); ok {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return 0, false, fmt.Errorf("unable to convert $BPI_JVM_CLASS_COUNT=%s to integer\n%w", s, err)
+		}
+		return n, true, nil
+	}
+
+	javaHome, ok := os.LookupEnv("JAVA_HOME")
+	if !ok {
+		return 0, false, nil
+	}
+
+	n, err := count.Classes(javaHome)
+	if err != nil {
+		return 0, false, fmt.Errorf("unable to count classes in %s\n%w", javaHome, err)
+	}
+
+	return n, true, nil
+}
+
+// LoadedClassCount estimates the number of classes loaded at runtime from the classes available in the JVM and the
+// application.
+func LoadedClassCount(jvmClasses int, applicationClasses int, factor float64) int {
+	return int(float64(jvmClasses+applicationClasses) * factor)
+}
+
+// ParseLoadedClassFactor parses a loaded class factor, which must be greater than 0 and at most 1.
+func ParseLoadedClassFactor(s string) (float64, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultLoadedClassFactor, nil
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse loaded class factor %s\n%w", s, err)
+	}
+
+	if f <= 0 || f > 1 {
+		return 0, fmt.Errorf("loaded class factor %s must be greater than 0 and at most 1", s)
+	}
+
+	return f, nil
+}
+
+// CountLoadedClasses counts the classes logged by -Xlog:class+load during a training run.
+func CountLoadedClasses(file string) (int, error) {
+	in, err := os.Open(file)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+	defer in.Close()
+
+	n := 0
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if strings.Contains(scanner.Text(), "[class,load]") {
+			n++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	return n, nil
+}
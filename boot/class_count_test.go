@@ -0,0 +1,86 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testClassCount(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	context("JVMClassCount", func() {
+		it("uses $BPI_JVM_CLASS_COUNT", func() {
+			t.Setenv("BPI_JVM_CLASS_COUNT", "21000")
+
+			n, ok, err := boot.JVMClassCount()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(n).To(Equal(21000))
+		})
+
+		it("returns an error for an invalid $BPI_JVM_CLASS_COUNT", func() {
+			t.Setenv("BPI_JVM_CLASS_COUNT", "many")
+
+			_, _, err := boot.JVMClassCount()
+			Expect(err).To(HaveOccurred())
+		})
+
+		it("is unknown without a JVM", func() {
+			t.Setenv("JAVA_HOME", "")
+			Expect(os.Unsetenv("JAVA_HOME")).To(Succeed())
+
+			_, ok, err := boot.JVMClassCount()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	it("estimates loaded classes", func() {
+		Expect(boot.LoadedClassCount(20000, 10000, 0.35)).To(Equal(10500))
+	})
+
+	it("parses loaded class factor", func() {
+		Expect(boot.ParseLoadedClassFactor("")).To(Equal(boot.DefaultLoadedClassFactor))
+		Expect(boot.ParseLoadedClassFactor("0.5")).To(Equal(0.5))
+
+		_, err := boot.ParseLoadedClassFactor("2")
+		Expect(err).To(HaveOccurred())
+
+		_, err = boot.ParseLoadedClassFactor("half")
+		Expect(err).To(HaveOccurred())
+	})
+
+	it("counts loaded classes in a class load log", func() {
+		file := filepath.Join(t.TempDir(), "class-load.log")
+		Expect(os.WriteFile(file, []byte(`[0.010s][info][class,load] java.lang.Object source: shared objects file
+[0.011s][info][gc] Using G1
+[0.012s][info][class,load] java.lang.String source: shared objects file
+`), 0644)).To(Succeed())
+
+		Expect(boot.CountLoadedClasses(file)).To(Equal(2))
+	})
+}
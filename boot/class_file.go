@@ -0,0 +1,176 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	classFileMagic = 0xCAFEBABE
+
+	// classFileMajorOffset is the difference between a class file major version and the Java release that produces it
+	classFileMajorOffset = 44
+)
+
+var (
+	multiReleaseManifest     = regexp.MustCompile(`(?mi)^Multi-Release:\s*true\s*$`)
+	multiReleaseVersionEntry = regexp.MustCompile(`^META-INF/versions/(\d+)/(.+)$`)
+)
+
+// ClassFileMajorVersion reads the major version from the header of a class file.
+func ClassFileMajorVersion(in io.Reader) (int, error) {
+	var header struct {
+		Magic uint32
+		Minor uint16
+		Major uint16
+	}
+
+	if err := binary.Read(in, binary.BigEndian, &header); err != nil {
+		return 0, fmt.Errorf("unable to read class file header\n%w", err)
+	}
+
+	if header.Magic != classFileMagic {
+		return 0, fmt.Errorf("invalid class file magic %x", header.Magic)
+	}
+
+	return int(header.Major), nil
+}
+
+// JavaVersionFromClassFileMajor returns the Java release targeted by a class file major version.
+func JavaVersionFromClassFileMajor(major int) int {
+	if major <= classFileMajorOffset {
+		return 0
+	}
+	return major - classFileMajorOffset
+}
+
+// BytecodeTarget is the highest Java release targeted by the classes of a directory or a jar.
+type BytecodeTarget struct {
+	// Path is the directory or jar containing the classes.
+	Path string
+
+	// Class is a class targeting Java.
+	Class string
+
+	// Java is the Java release targeted.
+	Java int
+}
+
+func (b BytecodeTarget) String() string {
+	return fmt.Sprintf("%s (%s) targets Java %d", b.Path, b.Class, b.Java)
+}
+
+// MaxJavaVersionInDirectory returns the highest Java release targeted by the class files under dir, and 0 if there are
+// none.
+func MaxJavaVersionInDirectory(dir string) (int, error) {
+	t, err := MaxBytecodeTargetInDirectory(dir)
+	if err != nil {
+		return 0, err
+	}
+	return t.Java, nil
+}
+
+// MaxBytecodeTargetInDirectory returns the highest Java release targeted by the class files under dir, and the class
+// targeting it.
+func MaxBytecodeTargetInDirectory(dir string) (BytecodeTarget, error) {
+	t := BytecodeTarget{Path: dir}
+
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || filepath.Ext(path) != ".class" {
+			return nil
+		}
+
+		in, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("unable to open %s\n%w", path, err)
+		}
+		defer in.Close()
+
+		major, err := ClassFileMajorVersion(in)
+		if err != nil {
+			// stubs and resources named like class files are not interesting here
+			return nil
+		}
+
+		if v := JavaVersionFromClassFileMajor(major); v > t.Java {
+			t.Java = v
+			if rel, err := filepath.Rel(dir, path); err == nil {
+				t.Class = className(filepath.ToSlash(rel))
+			}
+		}
+
+		return nil
+	}); err != nil && !os.IsNotExist(err) {
+		return BytecodeTarget{}, fmt.Errorf("unable to read class files in %s\n%w", dir, err)
+	}
+
+	return t, nil
+}
+
+// BytecodeTarget returns the highest Java release targeted by the class files of the jar that a JRE of release jre
+// loads, as recorded when the jar was indexed.
+func (e JarIndexEntry) BytecodeTarget(jre int) BytecodeTarget {
+	t := BytecodeTarget{Path: e.Path}
+	for _, v := range e.ClassVersions {
+		if v.Release > jre {
+			continue
+		}
+		if java := JavaVersionFromClassFileMajor(v.Major); java > t.Java {
+			t.Java, t.Class = java, v.Class
+		}
+	}
+	return t
+}
+
+// UnsupportedBytecodeTargets returns the classes directory and indexed jars whose classes target a Java release newer
+// than jre.
+func UnsupportedBytecodeTargets(classes string, index JarIndex, jre int) ([]BytecodeTarget, error) {
+	var targets []BytecodeTarget
+
+	if classes != "" {
+		t, err := MaxBytecodeTargetInDirectory(classes)
+		if err != nil {
+			return nil, err
+		}
+		if t.Java > jre {
+			targets = append(targets, t)
+		}
+	}
+
+	for _, e := range index.Jars {
+		if t := e.BytecodeTarget(jre); t.Java > jre {
+			targets = append(targets, t)
+		}
+	}
+
+	return targets, nil
+}
+
+func className(name string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(name, ".class"), "/", ".")
+}
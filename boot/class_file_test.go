@@ -0,0 +1,160 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func classFile(major byte) []byte {
+	return []byte{0xCA, 0xFE, 0xBA, 0xBE, 0x00, 0x00, 0x00, major}
+}
+
+func testClassFile(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = os.MkdirTemp("", "class-file")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	it("reads the major version", func() {
+		Expect(boot.ClassFileMajorVersion(bytes.NewReader(classFile(61)))).To(Equal(61))
+	})
+
+	it("rejects invalid class files", func() {
+		_, err := boot.ClassFileMajorVersion(bytes.NewReader([]byte{0x00, 0x01, 0x02, 0x03, 0x00, 0x00, 0x00, 0x3D}))
+		Expect(err).To(HaveOccurred())
+
+		_, err = boot.ClassFileMajorVersion(bytes.NewReader([]byte{0xCA, 0xFE}))
+		Expect(err).To(HaveOccurred())
+	})
+
+	it("maps major versions to Java releases", func() {
+		Expect(boot.JavaVersionFromClassFileMajor(52)).To(Equal(8))
+		Expect(boot.JavaVersionFromClassFileMajor(61)).To(Equal(17))
+		Expect(boot.JavaVersionFromClassFileMajor(69)).To(Equal(25))
+		Expect(boot.JavaVersionFromClassFileMajor(40)).To(Equal(0))
+	})
+
+	it("finds the highest Java release in a directory", func() {
+		Expect(os.MkdirAll(filepath.Join(path, "com", "example"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "com", "example", "A.class"), classFile(55), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "com", "example", "B.class"), classFile(61), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "com", "example", "C.class"), []byte{}, 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "application.properties"), []byte{}, 0644)).To(Succeed())
+
+		Expect(boot.MaxJavaVersionInDirectory(path)).To(Equal(17))
+	})
+
+	it("ignores missing directories", func() {
+		Expect(boot.MaxJavaVersionInDirectory(filepath.Join(path, "missing"))).To(Equal(0))
+	})
+
+	it("finds the class targeting the highest Java release in a directory", func() {
+		Expect(os.MkdirAll(filepath.Join(path, "com", "example"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "com", "example", "A.class"), classFile(55), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "com", "example", "B.class"), classFile(65), 0644)).To(Succeed())
+
+		target, err := boot.MaxBytecodeTargetInDirectory(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(target).To(Equal(boot.BytecodeTarget{Path: path, Class: "com.example.B", Java: 21}))
+	})
+
+	context("jars", func() {
+		it("finds the class targeting the highest Java release", func() {
+			jar := filepath.Join(path, "test.jar")
+			writeJar(t, jar, map[string]string{
+				"com/example/A.class": string(classFile(52)),
+				"com/example/B.class": string(classFile(61)),
+				"module-info.class":   string(classFile(69)),
+			})
+
+			index, err := boot.NewJarIndex([]string{jar}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(index.Jars[0].BytecodeTarget(17)).To(Equal(boot.BytecodeTarget{Path: jar, Class: "com.example.B", Java: 17}))
+		})
+
+		it("only considers multi-release versions the JRE loads", func() {
+			jar := filepath.Join(path, "test.jar")
+			writeJar(t, jar, map[string]string{
+				"META-INF/MANIFEST.MF":                     "Manifest-Version: 1.0\nMulti-Release: true\n",
+				"com/example/A.class":                      string(classFile(52)),
+				"META-INF/versions/11/com/example/A.class": string(classFile(55)),
+				"META-INF/versions/21/com/example/A.class": string(classFile(65)),
+			})
+
+			index, err := boot.NewJarIndex([]string{jar}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(index.Jars[0].BytecodeTarget(17).Java).To(Equal(11))
+			Expect(index.Jars[0].BytecodeTarget(21).Java).To(Equal(21))
+		})
+
+		it("ignores versions of jars that are not multi-release", func() {
+			jar := filepath.Join(path, "test.jar")
+			writeJar(t, jar, map[string]string{
+				"com/example/A.class":                      string(classFile(52)),
+				"META-INF/versions/21/com/example/A.class": string(classFile(65)),
+			})
+
+			index, err := boot.NewJarIndex([]string{jar}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(index.Jars[0].BytecodeTarget(21).Java).To(Equal(8))
+		})
+	})
+
+	it("returns classes and jars targeting a newer Java release", func() {
+		classes := filepath.Join(path, "classes")
+		Expect(os.MkdirAll(classes, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(classes, "A.class"), classFile(61), 0644)).To(Succeed())
+
+		older := filepath.Join(path, "older.jar")
+		writeJar(t, older, map[string]string{"com/example/A.class": string(classFile(52))})
+		newer := filepath.Join(path, "newer.jar")
+		writeJar(t, newer, map[string]string{"com/example/B.class": string(classFile(65))})
+
+		index, err := boot.NewJarIndex([]string{older, newer}, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		targets, err := boot.UnsupportedBytecodeTargets(classes, index, 17)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(Equal([]boot.BytecodeTarget{{Path: newer, Class: "com.example.B", Java: 21}}))
+
+		targets, err = boot.UnsupportedBytecodeTargets(classes, index, 11)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(HaveLen(2))
+		Expect(targets[0].String()).To(Equal(classes + " (A) targets Java 17"))
+	})
+}
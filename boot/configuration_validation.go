@@ -0,0 +1,211 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+const (
+	ConfigurationProblemUnknown    = "unknown"
+	ConfigurationProblemDeprecated = "deprecated"
+)
+
+// ConfigurationProblem is a property of a packaged configuration file that is not described by the configuration
+// metadata, or that is deprecated.
+type ConfigurationProblem struct {
+	// File is the path of the configuration file, relative to the application root.
+	File string
+
+	// Key is the name of the property.
+	Key string
+
+	// Kind is unknown or deprecated.
+	Kind string
+
+	// Deprecation describes deprecated properties.
+	Deprecation *Deprecation
+}
+
+// Unsupported returns whether the property is no longer supported, as deprecated with level error.
+func (c ConfigurationProblem) Unsupported() bool {
+	return c.Kind == ConfigurationProblemDeprecated && c.Deprecation.Level == "error"
+}
+
+func (c ConfigurationProblem) String() string {
+	if c.Kind == ConfigurationProblemUnknown {
+		return fmt.Sprintf("%s: %s is not a known property", c.File, c.Key)
+	}
+
+	s := fmt.Sprintf("%s: %s is deprecated", c.File, c.Key)
+	if c.Unsupported() {
+		s = fmt.Sprintf("%s: %s is no longer supported", c.File, c.Key)
+	}
+	if c.Deprecation.Replacement != "" {
+		s = fmt.Sprintf("%s, use %s instead", s, c.Deprecation.Replacement)
+	}
+	if c.Deprecation.Reason != "" {
+		s = fmt.Sprintf("%s (%s)", s, c.Deprecation.Reason)
+	}
+	return s
+}
+
+// ValidateConfiguration checks the properties of the configuration files in classes, including profile-specific
+// variants, against metadata. Properties matching a property of the metadata, or an entry of one of its Map or List
+// properties, are known. Only properties under a group of the metadata are reported as unknown, so that properties of
+// libraries without metadata are not.
+func ValidateConfiguration(appPath string, classes string, metadata ConfigurationMetadata) ([]ConfigurationProblem, error) {
+	if len(metadata.Properties) == 0 {
+		return nil, nil
+	}
+
+	documents, err := LoadConfigurationDocuments(filepath.Join(appPath, classes))
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string]Property, len(metadata.Properties))
+	for _, p := range metadata.Properties {
+		properties[CanonicalConfigurationName(p.Name)] = p
+	}
+
+	groups := make(map[string]bool, len(metadata.Groups))
+	for _, g := range metadata.Groups {
+		groups[CanonicalConfigurationName(g.Name)] = true
+	}
+
+	type location struct{ file, key string }
+	seen := make(map[location]bool)
+
+	var problems []ConfigurationProblem
+	for _, d := range documents {
+		file := filepath.ToSlash(filepath.Join(classes, d.Origin))
+
+		for k := range d.Properties {
+			l := location{file, k}
+			if seen[l] {
+				continue
+			}
+			seen[l] = true
+
+			p, known := lookupConfigurationProperty(properties, CanonicalConfigurationName(k))
+			switch {
+			case known && p.Deprecation != nil:
+				problems = append(problems, ConfigurationProblem{File: file, Key: k, Kind: ConfigurationProblemDeprecated, Deprecation: p.Deprecation})
+			case !known && inConfigurationGroup(groups, CanonicalConfigurationName(k)):
+				problems = append(problems, ConfigurationProblem{File: file, Key: k, Kind: ConfigurationProblemUnknown})
+			}
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		return problems[i].Key < problems[j].Key
+	})
+
+	return problems, nil
+}
+
+// lookupConfigurationProperty returns the property of a canonical key, or the Map or List property the key is an entry
+// of, such as logging.level for logging.level.org.springframework.
+func lookupConfigurationProperty(properties map[string]Property, key string) (Property, bool) {
+	if p, ok := properties[key]; ok {
+		return p, true
+	}
+
+	for prefix := key; ; {
+		i := strings.LastIndexAny(prefix, ".[")
+		if i <= 0 {
+			return Property{}, false
+		}
+		prefix = prefix[:i]
+
+		if p, ok := properties[prefix]; ok && isCollectionType(p.Type) {
+			return p, true
+		}
+	}
+}
+
+func isCollectionType(t string) bool {
+	for _, prefix := range []string{"java.util.Map<", "java.util.List<", "java.util.Set<", "java.util.Collection<"} {
+		if strings.HasPrefix(t, prefix) {
+			return true
+		}
+	}
+	return t == "java.util.Properties" || strings.HasSuffix(t, "[]")
+}
+
+// inConfigurationGroup returns whether a canonical key is below one of groups.
+func inConfigurationGroup(groups map[string]bool, key string) bool {
+	for prefix := key; ; {
+		i := strings.LastIndex(prefix, ".")
+		if i <= 0 {
+			return false
+		}
+		prefix = prefix[:i]
+
+		if groups[prefix] {
+			return true
+		}
+	}
+}
+
+// ReportConfigurationProblems validates the packaged configuration against the configuration metadata of the
+// application and its dependencies. It logs unknown and deprecated properties, and returns an error for properties
+// that are no longer supported.
+func ReportConfigurationProblems(logger bard.Logger, appPath string, classes string, index JarIndex) error {
+	md, err := NewConfigurationMetadataFromPath(filepath.Join(appPath, classes))
+	if err != nil {
+		return fmt.Errorf("unable to read configuration metadata from %s\n%w", classes, err)
+	}
+	jarMD, err := index.ConfigurationMetadata()
+	if err != nil {
+		return fmt.Errorf("unable to read configuration metadata\n%w", err)
+	}
+	md.Groups = append(md.Groups, jarMD.Groups...)
+	md.Properties = append(md.Properties, jarMD.Properties...)
+
+	problems, err := ValidateConfiguration(appPath, classes, md)
+	if err != nil {
+		return fmt.Errorf("unable to validate configuration\n%w", err)
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	var all, unsupported []string
+	for _, p := range problems {
+		all = append(all, p.String())
+		if p.Unsupported() {
+			unsupported = append(unsupported, p.String())
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("the packaged configuration uses properties that are no longer supported:\n  %s", strings.Join(unsupported, "\n  "))
+	}
+
+	logger.Header(Warningf("WARNING: the packaged configuration uses unknown or deprecated properties:\n  %s", strings.Join(all, "\n  ")))
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testConfigurationValidation(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath  string
+		metadata boot.ConfigurationMetadata
+	)
+
+	it.Before(func() {
+		appPath = t.TempDir()
+		Expect(os.MkdirAll(filepath.Join(appPath, "BOOT-INF", "classes"), 0755)).To(Succeed())
+
+		metadata = boot.ConfigurationMetadata{
+			Groups: []boot.Group{{Name: "server"}, {Name: "logging"}, {Name: "spring.datasource"}},
+			Properties: []boot.Property{
+				{Name: "server.port", Type: "java.lang.Integer"},
+				{Name: "server.servlet.context-path", Type: "java.lang.String"},
+				{Name: "logging.level", Type: "java.util.Map<java.lang.String,java.lang.String>"},
+				{Name: "spring.datasource.url", Type: "java.lang.String"},
+				{Name: "spring.datasource.initialization-mode", Deprecation: &boot.Deprecation{
+					Level: "error", Replacement: "spring.sql.init.mode", Reason: "Use spring.sql.init instead",
+				}},
+				{Name: "server.max-http-header-size", Deprecation: &boot.Deprecation{
+					Level: "warning", Replacement: "server.max-http-request-header-size",
+				}},
+			},
+		}
+	})
+
+	it("reports unknown and deprecated properties", func() {
+		Expect(os.WriteFile(filepath.Join(appPath, "BOOT-INF", "classes", "application.properties"), []byte(`
+server.prot=8080
+server.servlet.contextPath=/demo
+server.max-http-header-size=16KB
+logging.level.org.springframework=DEBUG
+app.custom=value
+`), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(appPath, "BOOT-INF", "classes", "application-prod.yml"), []byte(`
+spring:
+  datasource:
+    url: jdbc:postgresql://db/app
+    initialization-mode: always
+`), 0644)).To(Succeed())
+
+		problems, err := boot.ValidateConfiguration(appPath, "BOOT-INF/classes", metadata)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := make([]string, len(problems))
+		for i, p := range problems {
+			s[i] = p.String()
+		}
+		Expect(s).To(Equal([]string{
+			"BOOT-INF/classes/application-prod.yml: spring.datasource.initialization-mode is no longer supported, use spring.sql.init.mode instead (Use spring.sql.init instead)",
+			"BOOT-INF/classes/application.properties: server.max-http-header-size is deprecated, use server.max-http-request-header-size instead",
+			"BOOT-INF/classes/application.properties: server.prot is not a known property",
+		}))
+		Expect(problems[0].Unsupported()).To(BeTrue())
+		Expect(problems[1].Unsupported()).To(BeFalse())
+	})
+
+	it("does nothing without metadata", func() {
+		Expect(os.WriteFile(filepath.Join(appPath, "BOOT-INF", "classes", "application.properties"), []byte("server.prot=8080\n"), 0644)).To(Succeed())
+
+		Expect(boot.ValidateConfiguration(appPath, "BOOT-INF/classes", boot.ConfigurationMetadata{})).To(BeEmpty())
+	})
+
+	context("report", func() {
+		var (
+			index boot.JarIndex
+			out   *bytes.Buffer
+		)
+
+		it.Before(func() {
+			index = boot.JarIndex{Jars: []boot.JarIndexEntry{{
+				Path: "BOOT-INF/lib/spring-boot-autoconfigure-3.3.1.jar",
+				ConfigurationMetadata: `{
+  "groups": [{"name": "spring.datasource"}],
+  "properties": [
+    {"name": "spring.datasource.url", "type": "java.lang.String"},
+    {"name": "spring.datasource.initialization-mode", "deprecation": {"level": "error", "replacement": "spring.sql.init.mode"}}
+  ]
+}`,
+			}}}
+			out = &bytes.Buffer{}
+		})
+
+		it("warns about unknown properties", func() {
+			Expect(os.WriteFile(filepath.Join(appPath, "BOOT-INF", "classes", "application.properties"),
+				[]byte("spring.datasource.ulr=jdbc:h2:mem:test\n"), 0644)).To(Succeed())
+
+			Expect(boot.ReportConfigurationProblems(bard.NewLogger(out), appPath, "BOOT-INF/classes", index)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("BOOT-INF/classes/application.properties: spring.datasource.ulr is not a known property"))
+		})
+
+		it("fails with properties that are no longer supported", func() {
+			Expect(os.WriteFile(filepath.Join(appPath, "BOOT-INF", "classes", "application.properties"),
+				[]byte("spring.datasource.initialization-mode=always\n"), 0644)).To(Succeed())
+
+			err := boot.ReportConfigurationProblems(bard.NewLogger(out), appPath, "BOOT-INF/classes", index)
+			Expect(err).To(MatchError(ContainSubstring("spring.datasource.initialization-mode is no longer supported, use spring.sql.init.mode instead")))
+		})
+	})
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Dependency is a jar of the application. Its group, name and version are the Maven coordinates embedded in the jar
+// when present, and follow Maven naming conventions of its file name otherwise.
+type Dependency struct {
+	Group   string `toml:"group,omitempty"`
+	Name    string `toml:"name"`
+	Version string `toml:"version"`
+	SHA256  string `toml:"sha256"`
+
+	// Licenses are the SPDX identifiers, or names if unknown, of the licenses of the jar.
+	Licenses []string `toml:"licenses,omitempty"`
+}
+
+func (d Dependency) String() string {
+	if d.Group == "" {
+		return fmt.Sprintf("%s:%s", d.Name, d.Version)
+	}
+	return fmt.Sprintf("%s:%s:%s", d.Group, d.Name, d.Version)
+}
+
+// NewDependencies lists the jars of index, identified by their Maven coordinates or, when they have none, by the Maven
+// naming conventions of their file name.
+func NewDependencies(index JarIndex) []Dependency {
+	dependencies := make([]Dependency, 0, len(index.Jars))
+	for _, e := range index.Jars {
+		d := Dependency{Name: filepath.Base(e.Path), Version: "unknown", SHA256: e.SHA256, Licenses: NormalizeLicenses(e.Licenses)}
+
+		if c, ok := e.MavenCoordinates(); ok {
+			d.Group, d.Name, d.Version = c.GroupID, c.ArtifactID, c.Version
+		} else if m := mavenJARName.FindStringSubmatch(filepath.Base(e.Path)); m != nil {
+			d.Name, d.Version = m[1], m[2]
+		}
+
+		dependencies = append(dependencies, d)
+	}
+
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].Name != dependencies[j].Name {
+			return dependencies[i].Name < dependencies[j].Name
+		}
+		if dependencies[i].Group != dependencies[j].Group {
+			return dependencies[i].Group < dependencies[j].Group
+		}
+		if dependencies[i].Version != dependencies[j].Version {
+			return dependencies[i].Version < dependencies[j].Version
+		}
+		return dependencies[i].SHA256 < dependencies[j].SHA256
+	})
+
+	return dependencies
+}
+
+// FindDependency returns the dependency with an artifact name. Dependencies whose group is known must also belong to
+// group.
+func FindDependency(dependencies []Dependency, group string, name string) (Dependency, bool) {
+	for _, d := range dependencies {
+		if d.Name == name && (d.Group == "" || group == "" || d.Group == group) {
+			return d, true
+		}
+	}
+	return Dependency{}, false
+}
+
+// MavenCoordinates returns the coordinates of the jar, read from its pom.properties or, when the jar has none, from the
+// Implementation-Vendor-Id, Implementation-Version and Bundle-Version attributes of its manifest with the artifact
+// named after the file.
+func (e JarIndexEntry) MavenCoordinates() (MavenCoordinates, bool) {
+	if len(e.Coordinates) > 0 {
+		// shaded jars contain the coordinates of their dependencies too, prefer those matching the file name
+		base := filepath.Base(e.Path)
+		for _, c := range e.Coordinates {
+			if strings.HasPrefix(base, fmt.Sprintf("%s-%s", c.ArtifactID, c.Version)) {
+				return c, true
+			}
+		}
+		return e.Coordinates[0], true
+	}
+
+	m := mavenJARName.FindStringSubmatch(filepath.Base(e.Path))
+	if m == nil {
+		return MavenCoordinates{}, false
+	}
+
+	c := MavenCoordinates{GroupID: e.Manifest["Implementation-Vendor-Id"], ArtifactID: m[1], Version: e.Manifest["Implementation-Version"]}
+	if c.Version == "" {
+		c.Version = e.Manifest["Bundle-Version"]
+	}
+
+	return c, c.GroupID != "" && c.Version != ""
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testDependency(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		path = t.TempDir()
+
+		writeJar(t, filepath.Join(path, "core-1.2.jar"), map[string]string{
+			"META-INF/maven/com.example/example-core/pom.properties": "groupId=com.example\nartifactId=example-core\nversion=1.2.0\n",
+		})
+		writeJar(t, filepath.Join(path, "util-2.0.jar"), map[string]string{
+			"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\nImplementation-Vendor-Id: org.example\nBundle-Version: 2.0.1\n",
+		})
+		writeJar(t, filepath.Join(path, "plain-3.0.jar"), map[string]string{})
+		writeJar(t, filepath.Join(path, "unversioned.jar"), map[string]string{})
+	})
+
+	it("identifies jars by their Maven coordinates", func() {
+		index, err := boot.NewJarIndexFromDirectory(path, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		dependencies := boot.NewDependencies(index)
+
+		Expect(dependencies).To(HaveLen(4))
+		Expect(dependencies[0]).To(MatchFields(IgnoreExtras, Fields{"Group": Equal("com.example"), "Name": Equal("example-core"), "Version": Equal("1.2.0")}))
+		Expect(dependencies[1]).To(Equal(boot.Dependency{Name: "plain", Version: "3.0", SHA256: index.Jars[1].SHA256}))
+		Expect(dependencies[2]).To(Equal(boot.Dependency{Name: "unversioned.jar", Version: "unknown", SHA256: index.Jars[2].SHA256}))
+		Expect(dependencies[3]).To(MatchFields(IgnoreExtras, Fields{"Group": Equal("org.example"), "Name": Equal("util"), "Version": Equal("2.0.1")}))
+	})
+
+	it("prefers the coordinates matching the file name of shaded jars", func() {
+		e := boot.JarIndexEntry{
+			Path: "BOOT-INF/lib/demo-1.0.0.jar",
+			Coordinates: []boot.MavenCoordinates{
+				{GroupID: "org.shaded", ArtifactID: "shaded", Version: "2.0.0"},
+				{GroupID: "com.example", ArtifactID: "demo", Version: "1.0.0"},
+			},
+		}
+
+		c, ok := e.MavenCoordinates()
+		Expect(ok).To(BeTrue())
+		Expect(c).To(Equal(boot.MavenCoordinates{GroupID: "com.example", ArtifactID: "demo", Version: "1.0.0"}))
+	})
+
+	it("does not identify jars without group", func() {
+		e := boot.JarIndexEntry{Path: "BOOT-INF/lib/demo-1.0.0.jar", Manifest: map[string]string{"Implementation-Version": "1.0.0"}}
+
+		_, ok := e.MavenCoordinates()
+		Expect(ok).To(BeFalse())
+	})
+
+	it("finds dependencies by group and name", func() {
+		dependencies := []boot.Dependency{
+			{Group: "org.example", Name: "core", Version: "1.0.0"},
+			{Name: "util", Version: "2.0.0"},
+		}
+
+		_, ok := boot.FindDependency(dependencies, "com.example", "core")
+		Expect(ok).To(BeFalse())
+
+		d, ok := boot.FindDependency(dependencies, "org.example", "core")
+		Expect(ok).To(BeTrue())
+		Expect(d.Version).To(Equal("1.0.0"))
+
+		d, ok = boot.FindDependency(dependencies, "org.example", "util")
+		Expect(ok).To(BeTrue())
+		Expect(d.Version).To(Equal("2.0.0"))
+	})
+}
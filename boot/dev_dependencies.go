@@ -0,0 +1,141 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/magiconair/properties"
+)
+
+// DevDependencies are the artifacts only needed during development, which Spring Boot build plugins normally leave out
+// of the packaged application.
+var DevDependencies = []string{
+	"spring-boot-devtools",
+	"spring-boot-docker-compose",
+	"spring-boot-testcontainers",
+	"testcontainers",
+}
+
+// FindDevDependencies returns the paths, relative to appPath, of the jars under lib whose name, following Maven naming
+// conventions, is one of DevDependencies.
+func FindDevDependencies(appPath string, lib string) ([]string, error) {
+	root := filepath.Join(appPath, lib)
+
+	var found []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) && path == root {
+			return filepath.SkipDir
+		} else if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".jar" {
+			return nil
+		}
+
+		name := filepath.Base(path)
+		if m := mavenJARName.FindStringSubmatch(name); m != nil {
+			name = m[1]
+		}
+		for _, d := range DevDependencies {
+			if name == d {
+				rel, err := filepath.Rel(appPath, path)
+				if err != nil {
+					return err
+				}
+				found = append(found, filepath.ToSlash(rel))
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to walk %s\n%w", root, err)
+	}
+
+	sort.Strings(found)
+	return found, nil
+}
+
+// RemoveDevDependencies deletes the development-only jars under lib, and their entries in the classpath and layers
+// indexes named by manifest. It returns the paths of the removed jars, relative to appPath.
+func RemoveDevDependencies(appPath string, lib string, manifest *properties.Properties) ([]string, error) {
+	found, err := FindDevDependencies(appPath, lib)
+	if err != nil || len(found) == 0 {
+		return nil, err
+	}
+
+	removed := make(map[string]bool, len(found))
+	for _, f := range found {
+		if err := os.Remove(filepath.Join(appPath, f)); err != nil {
+			return nil, fmt.Errorf("unable to remove %s\n%w", f, err)
+		}
+		removed[f] = true
+	}
+
+	for _, key := range []string{"Spring-Boot-Classpath-Index", "Spring-Boot-Layers-Index"} {
+		if index, ok := manifest.Get(key); ok {
+			if err := removeIndexEntries(filepath.Join(appPath, index), removed); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// removeIndexEntries removes the lines of classpath.idx or layers.idx listing removed jars, either by path or, as in
+// the classpath.idx of Spring Boot 2.3 and 2.4, by file name.
+func removeIndexEntries(file string, removed map[string]bool) error {
+	b, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to read %s\n%w", file, err)
+	}
+
+	names := make(map[string]bool, len(removed))
+	for r := range removed {
+		names[filepath.Base(r)] = true
+	}
+
+	var kept []string
+	changed := false
+	for _, line := range strings.SplitAfter(string(b), "\n") {
+		entry := strings.Trim(strings.TrimPrefix(strings.TrimSpace(line), "- "), `"`)
+		if removed[entry] || names[entry] {
+			changed = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if err := os.WriteFile(file, []byte(strings.Join(kept, "")), 0644); err != nil {
+		return fmt.Errorf("unable to write %s\n%w", file, err)
+	}
+
+	return nil
+}
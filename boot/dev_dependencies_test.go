@@ -0,0 +1,115 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/magiconair/properties"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testDevDependencies(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		appPath  string
+		manifest *properties.Properties
+	)
+
+	it.Before(func() {
+		appPath = t.TempDir()
+
+		lib := filepath.Join(appPath, "BOOT-INF", "lib")
+		Expect(os.MkdirAll(lib, 0755)).To(Succeed())
+		for _, jar := range []string{
+			"spring-boot-devtools-3.3.1.jar",
+			"spring-boot-docker-compose-3.3.1.jar",
+			"testcontainers-1.19.8.jar",
+			"spring-core-6.1.10.jar",
+			"testcontainers-extras-1.0.0.jar",
+		} {
+			Expect(os.WriteFile(filepath.Join(lib, jar), []byte{}, 0644)).To(Succeed())
+		}
+
+		Expect(os.WriteFile(filepath.Join(appPath, "BOOT-INF", "classpath.idx"), []byte(`- "BOOT-INF/lib/spring-boot-devtools-3.3.1.jar"
+- "BOOT-INF/lib/spring-boot-docker-compose-3.3.1.jar"
+- "BOOT-INF/lib/testcontainers-1.19.8.jar"
+- "BOOT-INF/lib/spring-core-6.1.10.jar"
+- "BOOT-INF/lib/testcontainers-extras-1.0.0.jar"
+`), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(appPath, "BOOT-INF", "layers.idx"), []byte(`- "dependencies":
+  - "BOOT-INF/lib/spring-core-6.1.10.jar"
+  - "BOOT-INF/lib/testcontainers-extras-1.0.0.jar"
+- "development":
+  - "BOOT-INF/lib/spring-boot-devtools-3.3.1.jar"
+- "application":
+  - "BOOT-INF/classes/"
+`), 0644)).To(Succeed())
+
+		manifest = properties.LoadMap(map[string]string{
+			"Spring-Boot-Classpath-Index": "BOOT-INF/classpath.idx",
+			"Spring-Boot-Layers-Index":    "BOOT-INF/layers.idx",
+		})
+	})
+
+	it("finds development-only dependencies", func() {
+		Expect(boot.FindDevDependencies(appPath, "BOOT-INF/lib")).To(Equal([]string{
+			"BOOT-INF/lib/spring-boot-devtools-3.3.1.jar",
+			"BOOT-INF/lib/spring-boot-docker-compose-3.3.1.jar",
+			"BOOT-INF/lib/testcontainers-1.19.8.jar",
+		}))
+	})
+
+	it("removes development-only dependencies and their index entries", func() {
+		removed, err := boot.RemoveDevDependencies(appPath, "BOOT-INF/lib", manifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(HaveLen(3))
+
+		Expect(filepath.Join(appPath, "BOOT-INF", "lib", "spring-boot-devtools-3.3.1.jar")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(appPath, "BOOT-INF", "lib", "spring-core-6.1.10.jar")).To(BeARegularFile())
+
+		Expect(os.ReadFile(filepath.Join(appPath, "BOOT-INF", "classpath.idx"))).To(Equal([]byte(`- "BOOT-INF/lib/spring-core-6.1.10.jar"
+- "BOOT-INF/lib/testcontainers-extras-1.0.0.jar"
+`)))
+		Expect(os.ReadFile(filepath.Join(appPath, "BOOT-INF", "layers.idx"))).To(Equal([]byte(`- "dependencies":
+  - "BOOT-INF/lib/spring-core-6.1.10.jar"
+  - "BOOT-INF/lib/testcontainers-extras-1.0.0.jar"
+- "development":
+- "application":
+  - "BOOT-INF/classes/"
+`)))
+	})
+
+	it("removes entries listing file names only", func() {
+		Expect(os.WriteFile(filepath.Join(appPath, "BOOT-INF", "classpath.idx"), []byte("- \"spring-boot-devtools-3.3.1.jar\"\n- \"spring-core-6.1.10.jar\"\n"), 0644)).To(Succeed())
+
+		_, err := boot.RemoveDevDependencies(appPath, "BOOT-INF/lib", manifest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.ReadFile(filepath.Join(appPath, "BOOT-INF", "classpath.idx"))).To(Equal([]byte("- \"spring-core-6.1.10.jar\"\n")))
+	})
+
+	it("does nothing without lib directory", func() {
+		Expect(boot.RemoveDevDependencies(appPath, "lib", manifest)).To(BeEmpty())
+	})
+}
@@ -0,0 +1,263 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// DuplicateClassExamples is the number of example classes reported for each pair of jars.
+const DuplicateClassExamples = 3
+
+// IgnoredDuplicateClasses are classes every modular or annotated jar may contain, which the JVM never confuses.
+var IgnoredDuplicateClasses = []string{
+	"module-info",
+	"*.package-info",
+}
+
+// DuplicateClasses are the classes two jars both provide.
+type DuplicateClasses struct {
+	// Jars are the paths of the two jars.
+	Jars [2]string
+
+	// Count is the number of classes both jars provide.
+	Count int
+
+	// Examples are some of the classes both jars provide.
+	Examples []string
+}
+
+func (d DuplicateClasses) String() string {
+	return fmt.Sprintf("%s and %s: %d classes, such as %s",
+		filepath.Base(d.Jars[0]), filepath.Base(d.Jars[1]), d.Count, strings.Join(d.Examples, ", "))
+}
+
+// SplitPackages are the packages two jars both provide different classes of.
+type SplitPackages struct {
+	// Jars are the paths of the two jars.
+	Jars [2]string
+
+	// Count is the number of packages split between the jars.
+	Count int
+
+	// Examples are some of the packages split between the jars.
+	Examples []string
+}
+
+func (s SplitPackages) String() string {
+	return fmt.Sprintf("%s and %s: %d packages, such as %s",
+		filepath.Base(s.Jars[0]), filepath.Base(s.Jars[1]), s.Count, strings.Join(s.Examples, ", "))
+}
+
+// FindDuplicateClasses returns the classes provided by more than one jar of index, grouped by pair of jars. Jars whose
+// file name, and classes whose name, match one of the allowed glob patterns are ignored.
+func FindDuplicateClasses(index JarIndex, allowed []string) []DuplicateClasses {
+	providers := make(map[string][]int)
+	for i, classes := range comparedClasses(index, allowed) {
+		for _, c := range classes {
+			providers[c] = append(providers[c], i)
+		}
+	}
+
+	pairs := make(map[[2]int][]string)
+	for c, jars := range providers {
+		for i := 0; i < len(jars); i++ {
+			for j := i + 1; j < len(jars); j++ {
+				key := [2]int{jars[i], jars[j]}
+				pairs[key] = append(pairs[key], c)
+			}
+		}
+	}
+
+	var duplicates []DuplicateClasses
+	for key, classes := range pairs {
+		sort.Strings(classes)
+
+		d := DuplicateClasses{
+			Jars:  [2]string{index.Jars[key[0]].Path, index.Jars[key[1]].Path},
+			Count: len(classes),
+		}
+		if len(classes) > DuplicateClassExamples {
+			classes = classes[:DuplicateClassExamples]
+		}
+		d.Examples = classes
+
+		duplicates = append(duplicates, d)
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Jars[0] != duplicates[j].Jars[0] {
+			return duplicates[i].Jars[0] < duplicates[j].Jars[0]
+		}
+		return duplicates[i].Jars[1] < duplicates[j].Jars[1]
+	})
+
+	return duplicates
+}
+
+// FindSplitPackages returns the packages that more than one jar of index provides different classes of, grouped by
+// pair of jars. Jars providing the same classes of a package are reported by FindDuplicateClasses instead. Jars whose
+// file name, and classes whose name, match one of the allowed glob patterns are ignored.
+func FindSplitPackages(index JarIndex, allowed []string) []SplitPackages {
+	packages := make(map[string]map[int][]string)
+	for i, classes := range comparedClasses(index, allowed) {
+		for _, c := range classes {
+			n := strings.LastIndex(c, ".")
+			if n < 0 {
+				// the unnamed package cannot be split
+				continue
+			}
+			if packages[c[:n]] == nil {
+				packages[c[:n]] = make(map[int][]string)
+			}
+			packages[c[:n]][i] = append(packages[c[:n]][i], c)
+		}
+	}
+
+	pairs := make(map[[2]int][]string)
+	for p, jars := range packages {
+		var providers []int
+		for i := range jars {
+			providers = append(providers, i)
+		}
+		sort.Ints(providers)
+
+		for i := 0; i < len(providers); i++ {
+			for j := i + 1; j < len(providers); j++ {
+				if !sameClasses(jars[providers[i]], jars[providers[j]]) {
+					key := [2]int{providers[i], providers[j]}
+					pairs[key] = append(pairs[key], p)
+				}
+			}
+		}
+	}
+
+	var splits []SplitPackages
+	for key, packages := range pairs {
+		sort.Strings(packages)
+
+		s := SplitPackages{
+			Jars:  [2]string{index.Jars[key[0]].Path, index.Jars[key[1]].Path},
+			Count: len(packages),
+		}
+		if len(packages) > DuplicateClassExamples {
+			packages = packages[:DuplicateClassExamples]
+		}
+		s.Examples = packages
+
+		splits = append(splits, s)
+	}
+
+	sort.Slice(splits, func(i, j int) bool {
+		if splits[i].Jars[0] != splits[j].Jars[0] {
+			return splits[i].Jars[0] < splits[j].Jars[0]
+		}
+		return splits[i].Jars[1] < splits[j].Jars[1]
+	})
+
+	return splits
+}
+
+// comparedClasses returns the classes of each jar of index that are compared with other jars, in the order of the
+// index, leaving out allowed jars and classes.
+func comparedClasses(index JarIndex, allowed []string) [][]string {
+	matches := func(name string, patterns []string) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	compared := make([][]string, len(index.Jars))
+	for i, e := range index.Jars {
+		if matches(filepath.Base(e.Path), allowed) {
+			continue
+		}
+
+		for _, c := range e.Classes {
+			if matches(c, IgnoredDuplicateClasses) || matches(c, allowed) {
+				continue
+			}
+			compared[i] = append(compared[i], c)
+		}
+	}
+
+	return compared
+}
+
+func sameClasses(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	set := make(map[string]bool, len(a))
+	for _, c := range a {
+		set[c] = true
+	}
+	for _, c := range b {
+		if !set[c] {
+			return false
+		}
+	}
+	return true
+}
+
+// ReportDuplicateClasses logs the classes provided by, and the packages split between, more than one dependency, except
+// overlaps of jars matching the comma or space separated allowed patterns. It returns an error instead for duplicate
+// classes with the fail policy. Split packages are legal on the classpath, so they are only ever logged.
+func ReportDuplicateClasses(logger bard.Logger, index JarIndex, allowed string, policy string) error {
+	patterns := strings.FieldsFunc(allowed, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	if splits := FindSplitPackages(index, patterns); len(splits) > 0 {
+		s := make([]string, len(splits))
+		for i, p := range splits {
+			s[i] = p.String()
+		}
+		logger.Header(Warningf("WARNING: Packages are split between more than one dependency, which only matters if they are sealed jars or JPMS modules:\n  %s\n"+
+			"Allow the overlap with $BP_SPRING_BOOT_DUPLICATE_CLASSES_ALLOWED", strings.Join(s, "\n  ")))
+	}
+
+	duplicates := FindDuplicateClasses(index, patterns)
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	s := make([]string, len(duplicates))
+	for i, d := range duplicates {
+		s[i] = d.String()
+	}
+	message := fmt.Sprintf("Classes are provided by more than one dependency, the JVM loads whichever comes first on the classpath:\n  %s\n"+
+		"Remove one of the dependencies, or allow the overlap with $BP_SPRING_BOOT_DUPLICATE_CLASSES_ALLOWED", strings.Join(s, "\n  "))
+
+	if policy == "fail" {
+		return fmt.Errorf("%s", message)
+	}
+
+	logger.Header(Warningf("WARNING: %s", message))
+	return nil
+}
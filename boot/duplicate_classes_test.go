@@ -0,0 +1,154 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testDuplicateClasses(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		index boot.JarIndex
+	)
+
+	it.Before(func() {
+		index = boot.JarIndex{Jars: []boot.JarIndexEntry{
+			{Path: "BOOT-INF/lib/demo-1.0.0.jar", Classes: []string{"module-info", "com.example.A", "com.example.B", "com.example.C", "com.example.D", "com.example.package-info"}},
+			{Path: "BOOT-INF/lib/demo-2.0.0.jar", Classes: []string{"module-info", "com.example.A", "com.example.B", "com.example.C", "com.example.D", "com.example.package-info"}},
+			{Path: "BOOT-INF/lib/shaded-1.0.0.jar", Classes: []string{"com.example.A", "org.apache.commons.logging.Log"}},
+			{Path: "BOOT-INF/lib/spring-jcl-6.1.0.jar", Classes: []string{"org.apache.commons.logging.Log"}},
+		}}
+	})
+
+	it("groups duplicate classes by pair of jars", func() {
+		duplicates := boot.FindDuplicateClasses(index, nil)
+
+		Expect(duplicates).To(Equal([]boot.DuplicateClasses{
+			{
+				Jars:     [2]string{"BOOT-INF/lib/demo-1.0.0.jar", "BOOT-INF/lib/demo-2.0.0.jar"},
+				Count:    4,
+				Examples: []string{"com.example.A", "com.example.B", "com.example.C"},
+			},
+			{
+				Jars:     [2]string{"BOOT-INF/lib/demo-1.0.0.jar", "BOOT-INF/lib/shaded-1.0.0.jar"},
+				Count:    1,
+				Examples: []string{"com.example.A"},
+			},
+			{
+				Jars:     [2]string{"BOOT-INF/lib/demo-2.0.0.jar", "BOOT-INF/lib/shaded-1.0.0.jar"},
+				Count:    1,
+				Examples: []string{"com.example.A"},
+			},
+			{
+				Jars:     [2]string{"BOOT-INF/lib/shaded-1.0.0.jar", "BOOT-INF/lib/spring-jcl-6.1.0.jar"},
+				Count:    1,
+				Examples: []string{"org.apache.commons.logging.Log"},
+			},
+		}))
+		Expect(duplicates[0].String()).To(Equal("demo-1.0.0.jar and demo-2.0.0.jar: 4 classes, such as com.example.A, com.example.B, com.example.C"))
+	})
+
+	it("ignores allowed jars and classes", func() {
+		duplicates := boot.FindDuplicateClasses(index, []string{"shaded-*.jar", "com.example.*"})
+		Expect(duplicates).To(BeEmpty())
+
+		duplicates = boot.FindDuplicateClasses(index, []string{"org.apache.commons.logging.*"})
+		Expect(duplicates).To(HaveLen(3))
+	})
+
+	it("groups split packages by pair of jars", func() {
+		splits := boot.FindSplitPackages(index, nil)
+
+		Expect(splits).To(Equal([]boot.SplitPackages{
+			{
+				Jars:     [2]string{"BOOT-INF/lib/demo-1.0.0.jar", "BOOT-INF/lib/shaded-1.0.0.jar"},
+				Count:    1,
+				Examples: []string{"com.example"},
+			},
+			{
+				Jars:     [2]string{"BOOT-INF/lib/demo-2.0.0.jar", "BOOT-INF/lib/shaded-1.0.0.jar"},
+				Count:    1,
+				Examples: []string{"com.example"},
+			},
+		}))
+		Expect(splits[0].String()).To(Equal("demo-1.0.0.jar and shaded-1.0.0.jar: 1 packages, such as com.example"))
+	})
+
+	it("finds packages split without duplicate classes", func() {
+		index = boot.JarIndex{Jars: []boot.JarIndexEntry{
+			{Path: "BOOT-INF/lib/api-1.0.0.jar", Classes: []string{"javax.annotation.Nonnull", "javax.annotation.package-info"}},
+			{Path: "BOOT-INF/lib/jsr305-3.0.2.jar", Classes: []string{"javax.annotation.Nullable", "javax.annotation.package-info"}},
+		}}
+
+		Expect(boot.FindDuplicateClasses(index, nil)).To(BeEmpty())
+		Expect(boot.FindSplitPackages(index, nil)).To(Equal([]boot.SplitPackages{{
+			Jars:     [2]string{"BOOT-INF/lib/api-1.0.0.jar", "BOOT-INF/lib/jsr305-3.0.2.jar"},
+			Count:    1,
+			Examples: []string{"javax.annotation"},
+		}}))
+	})
+
+	it("ignores split packages of allowed jars and classes", func() {
+		Expect(boot.FindSplitPackages(index, []string{"shaded-*.jar"})).To(BeEmpty())
+		Expect(boot.FindSplitPackages(index, []string{"com.example.*"})).To(BeEmpty())
+	})
+
+	context("report", func() {
+		var out *bytes.Buffer
+
+		it.Before(func() {
+			out = &bytes.Buffer{}
+		})
+
+		it("warns about duplicate classes and split packages", func() {
+			Expect(boot.ReportDuplicateClasses(bard.NewLogger(out), index, "", "warn")).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("demo-1.0.0.jar and demo-2.0.0.jar: 4 classes"))
+			Expect(out.String()).To(ContainSubstring("demo-1.0.0.jar and shaded-1.0.0.jar: 1 packages, such as com.example"))
+		})
+
+		it("fails with the fail policy", func() {
+			err := boot.ReportDuplicateClasses(bard.NewLogger(out), index, "", "fail")
+			Expect(err).To(MatchError(ContainSubstring("shaded-1.0.0.jar and spring-jcl-6.1.0.jar: 1 classes, such as org.apache.commons.logging.Log")))
+		})
+
+		it("only warns about split packages with the fail policy", func() {
+			index = boot.JarIndex{Jars: []boot.JarIndexEntry{
+				{Path: "BOOT-INF/lib/demo-1.0.0.jar", Classes: []string{"com.example.A"}},
+				{Path: "BOOT-INF/lib/demo-extras-1.0.0.jar", Classes: []string{"com.example.B"}},
+			}}
+
+			Expect(boot.ReportDuplicateClasses(bard.NewLogger(out), index, "", "fail")).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("which only matters if they are sealed jars or JPMS modules"))
+			Expect(out.String()).To(ContainSubstring("demo-1.0.0.jar and demo-extras-1.0.0.jar: 1 packages, such as com.example"))
+		})
+
+		it("ignores overlaps allowed by comma or space separated patterns", func() {
+			Expect(boot.ReportDuplicateClasses(bard.NewLogger(out), index, "shaded-*.jar, com.example.*", "fail")).To(Succeed())
+			Expect(boot.ReportDuplicateClasses(bard.NewLogger(out), index, "shaded-*.jar com.example.*", "fail")).To(Succeed())
+			Expect(out.Len()).To(BeZero())
+		})
+	})
+}
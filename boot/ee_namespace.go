@@ -0,0 +1,165 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// EENamespace is the root package of the enterprise APIs, javax for Java EE and jakarta for Jakarta EE 9 and later.
+type EENamespace string
+
+const (
+	Javax   EENamespace = "javax"
+	Jakarta EENamespace = "jakarta"
+)
+
+// EEAPI is an enterprise API that moved from the javax to the jakarta namespace.
+type EEAPI struct {
+	// Name is the package of the API, without namespace.
+	Name string
+
+	// Types are the packages and types of the API, without namespace, in internal form. Only the types Jakarta EE took
+	// over are listed for packages shared with other specifications, such as javax.annotation with JSR 305.
+	Types []string
+}
+
+// EEAPIs are the enterprise APIs whose namespace must match the Spring Boot generation.
+var EEAPIs = []EEAPI{
+	{Name: "servlet", Types: []string{"servlet/"}},
+	{Name: "persistence", Types: []string{"persistence/"}},
+	{Name: "validation", Types: []string{"validation/"}},
+	{Name: "annotation", Types: []string{
+		"annotation/PostConstruct",
+		"annotation/PreDestroy",
+		"annotation/Priority",
+		"annotation/Resource",
+		"annotation/security/",
+	}},
+}
+
+// ExpectedEENamespace returns the namespace of the enterprise APIs a Spring Boot version is built on.
+func ExpectedEENamespace(version string) EENamespace {
+	if versionRespectsConstraint(version, ">= 3.0.0") {
+		return Jakarta
+	}
+	return Javax
+}
+
+// EENamespaceMismatch is a jar using enterprise APIs from the namespace the application does not use.
+type EENamespaceMismatch struct {
+	// Path is the path of the jar.
+	Path string
+
+	// Coordinates are the Maven coordinates of the jar, if known.
+	Coordinates []MavenCoordinates
+
+	// APIs are the enterprise APIs used from the wrong namespace, such as javax.servlet.
+	APIs []string
+}
+
+func (e EENamespaceMismatch) String() string {
+	name := filepath.Base(e.Path)
+	if len(e.Coordinates) > 0 {
+		c := e.Coordinates[0]
+		name = fmt.Sprintf("%s (%s:%s:%s)", name, c.GroupID, c.ArtifactID, c.Version)
+	}
+	return fmt.Sprintf("%s uses %s", name, strings.Join(e.APIs, ", "))
+}
+
+// EENamespaceMismatches returns the jars of index that use enterprise APIs from a different namespace than expected.
+// Jars referencing an API from both namespaces support either and are not reported.
+func EENamespaceMismatches(index JarIndex, expected EENamespace) []EENamespaceMismatch {
+	other := Javax
+	if expected == Javax {
+		other = Jakarta
+	}
+
+	var mismatches []EENamespaceMismatch
+	for _, e := range index.Jars {
+		references := make(map[string]bool, len(e.EEReferences))
+		for _, r := range e.EEReferences {
+			references[r] = true
+		}
+
+		var apis []string
+		for _, api := range EEAPIs {
+			wrong := fmt.Sprintf("%s.%s", other, api.Name)
+			if references[wrong] && !references[fmt.Sprintf("%s.%s", expected, api.Name)] {
+				apis = append(apis, wrong)
+			}
+		}
+
+		if len(apis) > 0 {
+			mismatches = append(mismatches, EENamespaceMismatch{Path: e.Path, Coordinates: e.Coordinates, APIs: apis})
+		}
+	}
+
+	return mismatches
+}
+
+// eeReferences adds the enterprise APIs, such as jakarta.servlet, referenced by the constant pool of a class file to
+// references.
+func eeReferences(class []byte, references map[string]bool) {
+	for _, namespace := range []EENamespace{Javax, Jakarta} {
+		if !bytes.Contains(class, []byte(namespace+"/")) {
+			continue
+		}
+
+		for _, api := range EEAPIs {
+			for _, t := range api.Types {
+				if bytes.Contains(class, []byte(string(namespace)+"/"+t)) {
+					references[fmt.Sprintf("%s.%s", namespace, api.Name)] = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// ReportEENamespaces logs the dependencies that use the enterprise API namespace a Spring Boot version does not use,
+// and returns an error instead with the fail policy.
+func ReportEENamespaces(logger bard.Logger, version string, index JarIndex, policy string) error {
+	if _, err := bootVersion(version); err != nil {
+		return nil
+	}
+
+	expected := ExpectedEENamespace(version)
+	mismatches := EENamespaceMismatches(index, expected)
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	s := make([]string, len(mismatches))
+	for i, m := range mismatches {
+		s[i] = m.String()
+	}
+	message := fmt.Sprintf("Spring Boot %s uses the %s namespace for enterprise APIs, but these dependencies use the other namespace and will fail at runtime:\n  %s",
+		version, expected, strings.Join(s, "\n  "))
+
+	if policy == "fail" {
+		return fmt.Errorf("%s", message)
+	}
+
+	logger.Header(Warningf("WARNING: %s", message))
+	return nil
+}
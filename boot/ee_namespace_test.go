@@ -0,0 +1,119 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testEENamespace(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		path = t.TempDir()
+	})
+
+	it("determines the namespace from the Spring Boot version", func() {
+		Expect(boot.ExpectedEENamespace("2.7.18")).To(Equal(boot.Javax))
+		Expect(boot.ExpectedEENamespace("3.0.0-M1")).To(Equal(boot.Jakarta))
+		Expect(boot.ExpectedEENamespace("4.0.0")).To(Equal(boot.Jakarta))
+	})
+
+	it("indexes enterprise API references", func() {
+		writeJar(t, filepath.Join(path, "legacy-1.0.0.jar"), map[string]string{
+			"com/example/Filter.class":   "\xca\xfe\xba\xbe Ljavax/servlet/Filter; Ljavax/annotation/Nullable;",
+			"com/example/Entity.class":   "\xca\xfe\xba\xbe javax/persistence/Entity",
+			"com/example/Resource.class": "\xca\xfe\xba\xbe Ljakarta/annotation/PostConstruct;",
+		})
+
+		index, err := boot.NewJarIndexFromDirectory(path, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.Jars[0].EEReferences).To(Equal([]string{"jakarta.annotation", "javax.persistence", "javax.servlet"}))
+	})
+
+	it("returns jars using the other namespace", func() {
+		index := boot.JarIndex{Jars: []boot.JarIndexEntry{
+			{
+				Path:         "BOOT-INF/lib/legacy-1.0.0.jar",
+				Coordinates:  []boot.MavenCoordinates{{GroupID: "com.example", ArtifactID: "legacy", Version: "1.0.0"}},
+				EEReferences: []string{"jakarta.annotation", "javax.persistence", "javax.servlet"},
+			},
+			{
+				Path:         "BOOT-INF/lib/dual-1.0.0.jar",
+				EEReferences: []string{"jakarta.servlet", "javax.servlet"},
+			},
+			{
+				Path:         "BOOT-INF/lib/modern-1.0.0.jar",
+				EEReferences: []string{"jakarta.servlet"},
+			},
+		}}
+
+		mismatches := boot.EENamespaceMismatches(index, boot.Jakarta)
+		Expect(mismatches).To(HaveLen(1))
+		Expect(mismatches[0].APIs).To(Equal([]string{"javax.servlet", "javax.persistence"}))
+		Expect(mismatches[0].String()).To(Equal("legacy-1.0.0.jar (com.example:legacy:1.0.0) uses javax.servlet, javax.persistence"))
+
+		mismatches = boot.EENamespaceMismatches(index, boot.Javax)
+		Expect(mismatches).To(HaveLen(2))
+		Expect(mismatches[0].String()).To(Equal("legacy-1.0.0.jar (com.example:legacy:1.0.0) uses jakarta.annotation"))
+		Expect(mismatches[1].String()).To(Equal("modern-1.0.0.jar uses jakarta.servlet"))
+	})
+
+	it("ignores jars without enterprise API references", func() {
+		Expect(boot.EENamespaceMismatches(boot.JarIndex{Jars: []boot.JarIndexEntry{{Path: "a.jar"}}}, boot.Jakarta)).To(BeEmpty())
+	})
+
+	context("report", func() {
+		var (
+			index boot.JarIndex
+			out   *bytes.Buffer
+		)
+
+		it.Before(func() {
+			index = boot.JarIndex{Jars: []boot.JarIndexEntry{{Path: "BOOT-INF/lib/legacy-1.0.0.jar", EEReferences: []string{"javax.servlet"}}}}
+			out = &bytes.Buffer{}
+		})
+
+		it("warns about mismatches", func() {
+			Expect(boot.ReportEENamespaces(bard.NewLogger(out), "3.3.1", index, "warn")).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("Spring Boot 3.3.1 uses the jakarta namespace for enterprise APIs"))
+			Expect(out.String()).To(ContainSubstring("legacy-1.0.0.jar uses javax.servlet"))
+		})
+
+		it("fails with the fail policy", func() {
+			err := boot.ReportEENamespaces(bard.NewLogger(out), "3.3.1", index, "fail")
+			Expect(err).To(MatchError(ContainSubstring("legacy-1.0.0.jar uses javax.servlet")))
+		})
+
+		it("ignores unknown Spring Boot versions", func() {
+			Expect(boot.ReportEENamespaces(bard.NewLogger(out), "", index, "fail")).To(Succeed())
+			Expect(out.Len()).To(BeZero())
+		})
+	})
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libjvm"
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+const (
+	ExplodedClasses = "classes"
+	ExplodedLib     = "lib"
+	ExplodedScripts = "bin"
+)
+
+var (
+	startScriptMainClass = regexp.MustCompile(`(?m)^\s*MAIN_CLASS=["']?([\w.$]+)`)
+	startScriptClasspath = regexp.MustCompile(`-classpath\s+"(?:\\.|[^"\\])*"\s*(?:\\\s*)?([A-Za-z_][\w.$]*)`)
+)
+
+// ExplodedApplication is an application laid out as a plain classpath, such as the output of `gradle installDist` or a
+// hand-assembled classes and lib directory. These applications do not carry a Spring-Boot-Version manifest entry, so
+// the Spring Boot version is inferred from the spring-boot JAR found in the lib directory.
+type ExplodedApplication struct {
+	Path      string
+	Classes   string
+	Lib       string
+	Version   string
+	MainClass string
+}
+
+// NewExplodedApplication inspects appPath for a plain classpath layout. It returns false if appPath does not contain a
+// lib directory with a spring-boot JAR.
+func NewExplodedApplication(appPath string, mainClass string) (ExplodedApplication, bool, error) {
+	lib := filepath.Join(appPath, ExplodedLib)
+	if ok, err := sherpa.DirExists(lib); err != nil {
+		return ExplodedApplication{}, false, fmt.Errorf("unable to check directory %s\n%w", lib, err)
+	} else if !ok {
+		return ExplodedApplication{}, false, nil
+	}
+
+	jars, err := libjvm.NewMavenJARListing(lib)
+	if err != nil {
+		return ExplodedApplication{}, false, fmt.Errorf("unable to generate dependencies from %s\n%w", lib, err)
+	}
+
+	version := ""
+	for _, jar := range jars {
+		if jar.Name == "spring-boot" {
+			version = jar.Version
+			break
+		}
+	}
+	if version == "" {
+		return ExplodedApplication{}, false, nil
+	}
+
+	if mainClass == "" {
+		if mainClass, err = mainClassFromStartScripts(filepath.Join(appPath, ExplodedScripts)); err != nil {
+			return ExplodedApplication{}, false, err
+		}
+	}
+
+	return ExplodedApplication{
+		Path:      appPath,
+		Classes:   ExplodedClasses,
+		Lib:       ExplodedLib,
+		Version:   version,
+		MainClass: mainClass,
+	}, true, nil
+}
+
+// Manifest returns a synthetic manifest describing the exploded application, equivalent to the one found in a Spring
+// Boot executable JAR.
+func (e ExplodedApplication) Manifest() *properties.Properties {
+	m := map[string]string{
+		"Spring-Boot-Version": e.Version,
+		"Spring-Boot-Classes": e.Classes,
+		"Spring-Boot-Lib":     e.Lib,
+	}
+	if e.MainClass != "" {
+		m["Main-Class"] = e.MainClass
+		m["Start-Class"] = e.MainClass
+	}
+	return properties.LoadMap(m)
+}
+
+// ClasspathString returns the classpath, relative to the application root, used to launch the exploded application.
+func (e ExplodedApplication) ClasspathString() string {
+	var cp []string
+
+	if ok, _ := sherpa.DirExists(filepath.Join(e.Path, e.Classes)); ok {
+		cp = append(cp, e.Classes)
+	}
+	cp = append(cp, e.Lib+"/*")
+
+	return strings.Join(cp, ":")
+}
+
+func mainClassFromStartScripts(dir string) (string, error) {
+	scripts, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("unable to read directory %s\n%w", dir, err)
+	}
+
+	for _, script := range scripts {
+		// Windows start scripts contain the same information as the POSIX ones
+		if script.IsDir() || filepath.Ext(script.Name()) == ".bat" {
+			continue
+		}
+
+		file := filepath.Join(dir, script.Name())
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("unable to read %s\n%w", file, err)
+		}
+
+		if m := startScriptMainClass.FindSubmatch(b); m != nil {
+			return string(m[1]), nil
+		}
+		if m := startScriptClasspath.FindSubmatch(b); m != nil {
+			return string(m[1]), nil
+		}
+	}
+
+	return "", nil
+}
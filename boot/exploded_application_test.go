@@ -0,0 +1,157 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testExplodedApplication(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = os.MkdirTemp("", "exploded-application")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(path, "lib"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(path, "bin"), 0755)).To(Succeed())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	it("returns false without a lib directory", func() {
+		Expect(os.RemoveAll(filepath.Join(path, "lib"))).To(Succeed())
+
+		_, ok, err := boot.NewExplodedApplication(path, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	it("returns false without a spring-boot jar", func() {
+		Expect(os.WriteFile(filepath.Join(path, "lib", "spring-core-6.1.2.jar"), []byte{}, 0644)).To(Succeed())
+
+		_, ok, err := boot.NewExplodedApplication(path, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	context("with a spring-boot jar", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(path, "lib", "spring-boot-3.2.1.jar"), []byte{}, 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(path, "lib", "spring-boot-autoconfigure-3.2.1.jar"), []byte{}, 0644)).To(Succeed())
+		})
+
+		it("infers the Spring Boot version", func() {
+			e, ok, err := boot.NewExplodedApplication(path, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			Expect(e.Version).To(Equal("3.2.1"))
+			Expect(e.MainClass).To(BeEmpty())
+		})
+
+		it("uses the provided main class", func() {
+			e, _, err := boot.NewExplodedApplication(path, "com.example.Provided")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(e.MainClass).To(Equal("com.example.Provided"))
+		})
+
+		it("reads the main class from a Gradle 8 start script", func() {
+			Expect(os.WriteFile(filepath.Join(path, "bin", "demo"), []byte(`#!/bin/sh
+CLASSPATH=$APP_HOME/lib/demo.jar:$APP_HOME/lib/spring-boot-3.2.1.jar
+
+set -- \
+        "-Dorg.gradle.appname=$APP_BASE_NAME" \
+        -classpath "$CLASSPATH" \
+        com.example.DemoApplication \
+        "$@"
+`), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(path, "bin", "demo.bat"), []byte(`set CLASSPATH=%APP_HOME%\lib\demo.jar`), 0644)).To(Succeed())
+
+			e, _, err := boot.NewExplodedApplication(path, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(e.MainClass).To(Equal("com.example.DemoApplication"))
+		})
+
+		it("reads the main class from a legacy start script", func() {
+			Expect(os.WriteFile(filepath.Join(path, "bin", "demo"), []byte(`#!/bin/sh
+eval set -- $DEFAULT_JVM_OPTS $JAVA_OPTS $DEMO_OPTS -classpath "\"$CLASSPATH\"" com.example.LegacyApplication "$APP_ARGS"
+`), 0755)).To(Succeed())
+
+			e, _, err := boot.NewExplodedApplication(path, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(e.MainClass).To(Equal("com.example.LegacyApplication"))
+		})
+
+		it("reads the main class from a MAIN_CLASS variable", func() {
+			Expect(os.WriteFile(filepath.Join(path, "bin", "demo"), []byte(`#!/bin/sh
+MAIN_CLASS=com.example.VariableApplication
+`), 0755)).To(Succeed())
+
+			e, _, err := boot.NewExplodedApplication(path, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(e.MainClass).To(Equal("com.example.VariableApplication"))
+		})
+
+		it("creates a synthetic manifest", func() {
+			e, _, err := boot.NewExplodedApplication(path, "com.example.Provided")
+			Expect(err).NotTo(HaveOccurred())
+
+			m := e.Manifest()
+			Expect(m.MustGet("Spring-Boot-Version")).To(Equal("3.2.1"))
+			Expect(m.MustGet("Spring-Boot-Classes")).To(Equal("classes"))
+			Expect(m.MustGet("Spring-Boot-Lib")).To(Equal("lib"))
+			Expect(m.MustGet("Main-Class")).To(Equal("com.example.Provided"))
+			Expect(m.MustGet("Start-Class")).To(Equal("com.example.Provided"))
+		})
+
+		it("creates a classpath from lib", func() {
+			e, _, err := boot.NewExplodedApplication(path, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(e.ClasspathString()).To(Equal("lib/*"))
+		})
+
+		it("creates a classpath from classes and lib", func() {
+			Expect(os.MkdirAll(filepath.Join(path, "classes"), 0755)).To(Succeed())
+
+			e, _, err := boot.NewExplodedApplication(path, "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(e.ClasspathString()).To(Equal("classes:lib/*"))
+		})
+	})
+}
@@ -25,14 +25,36 @@ import (
 
 func TestUnit(t *testing.T) {
 	suite := spec.New("boot", spec.Report(report.Terminal{}))
+	suite("Actuator", testActuator)
+	suite("ApplicationConfiguration", testApplicationConfiguration)
 	suite("Build", testBuild)
+	suite("ClassCount", testClassCount)
+	suite("ClassFile", testClassFile)
 	suite("ConfigurationMetadata", testConfigurationMetadata)
+	suite("ConfigurationValidation", testConfigurationValidation)
+	suite("Dependency", testDependency)
 	suite("Detect", testDetect)
+	suite("DevDependencies", testDevDependencies)
+	suite("DuplicateClasses", testDuplicateClasses)
+	suite("EENamespace", testEENamespace)
+	suite("ExplodedApplication", testExplodedApplication)
 	suite("GenerationValidator", testGenerationValidator)
+	suite("JavaCompatibility", testJavaCompatibility)
+	suite("JarIndex", testJarIndex)
+	suite("License", testLicense)
+	suite("MavenRepository", testMavenRepository)
+	suite("Policy", testPolicy)
+	suite("Provenance", testProvenance)
+	suite("SBOM", testSBOM)
+	suite("Secrets", testSecrets)
 	suite("SpringCloudBindings", testSpringCloudBindings)
+	suite("SpringEcosystem", testSpringEcosystem)
 	suite("SpringPerformance", testSpringPerformance)
+	suite("ThinLauncher", testThinLauncher)
+	suite("Vulnerability", testVulnerability)
 	suite("WebApplicationType", testWebApplicationType)
 	suite("WebApplicationTypeResolver", testWebApplicationTypeResolver)
+	suite("Workload", testWorkload)
 	suite("NativeImage", testNativeImage)
 	suite("JavaVersion", testJavaMajorVersion)
 	suite.Run(t)
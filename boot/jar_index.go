@@ -0,0 +1,571 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
+)
+
+const (
+	// JarIndexLayer is the name of the cache layer holding the index of previous builds.
+	JarIndexLayer = "jar-index"
+
+	// jarIndexVersion changes whenever entries gain information, so that entries cached by earlier builds are not reused.
+	jarIndexVersion = "7"
+
+	// jarIndexFile is the file of the jar index layer holding the entries.
+	jarIndexFile = "jar-index.json"
+
+	configurationMetadataEntry = "META-INF/spring-configuration-metadata.json"
+)
+
+// identityManifestAttributes are the manifest attributes indexed to identify jars without pom.properties.
+var identityManifestAttributes = []string{
+	"Implementation-Title",
+	"Implementation-Vendor-Id",
+	"Implementation-Version",
+	"Bundle-SymbolicName",
+	"Bundle-Version",
+}
+
+var (
+	pomPropertiesEntry = regexp.MustCompile(`^META-INF/maven/[^/]+/[^/]+/pom\.properties$`)
+	pomEntry           = regexp.MustCompile(`^META-INF/maven/[^/]+/[^/]+/pom\.xml$`)
+)
+
+// JarIndexEntry is what the build learns about a single jar.
+type JarIndexEntry struct {
+	// Path is the path of the jar.
+	Path string `toml:"path" json:"path"`
+
+	// SHA256 is the digest of the jar, used to reuse the entry in later builds.
+	SHA256 string `toml:"sha256" json:"sha256"`
+
+	// Classes are the names of the classes in the jar, including those only present in multi-release versions.
+	Classes []string `toml:"classes,omitempty" json:"classes,omitempty"`
+
+	// Resources are the names of the entries in the jar that are not classes or directories.
+	Resources []string `toml:"resources,omitempty" json:"resources,omitempty"`
+
+	// Coordinates are the Maven coordinates read from META-INF/maven/**/pom.properties.
+	Coordinates []MavenCoordinates `toml:"coordinates,omitempty" json:"coordinates,omitempty"`
+
+	// ConfigurationMetadata is the content of META-INF/spring-configuration-metadata.json.
+	ConfigurationMetadata string `toml:"configuration-metadata,omitempty" json:"configuration-metadata,omitempty"`
+
+	// EEReferences are the enterprise APIs referenced by the classes of the jar, such as javax.servlet.
+	EEReferences []string `toml:"ee-references,omitempty" json:"ee-references,omitempty"`
+
+	// Licenses are the licenses declared by the Bundle-License manifest entry and the Maven POMs of the jar, and
+	// identified from its META-INF/LICENSE files.
+	Licenses []string `toml:"licenses,omitempty" json:"licenses,omitempty"`
+
+	// Manifest are the Implementation-* and Bundle-* attributes of the manifest of the jar identifying it.
+	Manifest map[string]string `toml:"manifest,omitempty" json:"manifest,omitempty"`
+
+	// ClassVersions are the highest class file versions of the jar, for its base classes and for each multi-release
+	// version a JRE may load instead. Module descriptors are ignored.
+	ClassVersions []ClassVersion `toml:"class-versions,omitempty" json:"class-versions,omitempty"`
+}
+
+// ClassVersion is the highest class file version of the classes of a jar in a multi-release version.
+type ClassVersion struct {
+	// Release is the multi-release version containing the classes, 0 for the base classes.
+	Release int `toml:"release,omitempty" json:"release,omitempty"`
+
+	// Class is a class of the highest class file version.
+	Class string `toml:"class" json:"class"`
+
+	// Major is the class file major version.
+	Major int `toml:"major" json:"major"`
+}
+
+// JarIndex indexes every jar of an application once, so that analyzers do not need to reopen them.
+type JarIndex struct {
+	Jars []JarIndexEntry
+}
+
+// NewJarIndex indexes jars with a bounded number of workers. Entries of cached, keyed by digest, are reused for jars
+// that have not changed. Indexing stops at the first jar that cannot be read.
+func NewJarIndex(jars []string, cached map[string]JarIndexEntry) (JarIndex, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	entries := make([]JarIndexEntry, len(jars))
+	jobs := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(jars) {
+		workers = len(jars)
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				e, err := indexJar(jars[i], cached)
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				entries[i] = e
+			}
+		}()
+	}
+
+send:
+	for i := range jars {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return JarIndex{}, firstErr
+	}
+
+	return JarIndex{Jars: entries}, nil
+}
+
+// NewJarIndexFromDirectory indexes the jars directly contained in dir.
+func NewJarIndexFromDirectory(dir string, cached map[string]JarIndexEntry) (JarIndex, error) {
+	file := filepath.Join(dir, "*.jar")
+	jars, err := filepath.Glob(file)
+	if err != nil {
+		return JarIndex{}, fmt.Errorf("unable to glob %s\n%w", file, err)
+	}
+	sort.Strings(jars)
+
+	return NewJarIndex(jars, cached)
+}
+
+// NewApplicationJarIndex indexes the jars directly contained in the lib directory of the application at appPath. The
+// paths of the entries are relative to appPath, as they are laid out in the image.
+func NewApplicationJarIndex(appPath string, lib string, cached map[string]JarIndexEntry) (JarIndex, error) {
+	index, err := NewJarIndexFromDirectory(filepath.Join(appPath, lib), cached)
+	if err != nil {
+		return JarIndex{}, err
+	}
+
+	for i, e := range index.Jars {
+		if index.Jars[i].Path, err = filepath.Rel(appPath, e.Path); err != nil {
+			return JarIndex{}, fmt.Errorf("unable to determine relative path from %s to %s\n%w", appPath, e.Path, err)
+		}
+	}
+
+	return index, nil
+}
+
+// Classes returns the names of the classes found in all jars.
+func (j JarIndex) Classes() map[string]interface{} {
+	classes := make(map[string]interface{})
+	for _, e := range j.Jars {
+		for _, c := range e.Classes {
+			classes[c] = c
+		}
+	}
+	return classes
+}
+
+// ConfigurationMetadata returns the configuration metadata merged from all jars.
+func (j JarIndex) ConfigurationMetadata() (ConfigurationMetadata, error) {
+	var md ConfigurationMetadata
+
+	for _, e := range j.Jars {
+		if e.ConfigurationMetadata == "" {
+			continue
+		}
+
+		var c ConfigurationMetadata
+		if err := json.Unmarshal([]byte(e.ConfigurationMetadata), &c); err != nil {
+			return ConfigurationMetadata{}, fmt.Errorf("unable to decode %s in %s\n%w", configurationMetadataEntry, e.Path, err)
+		}
+
+		md.Groups = append(md.Groups, c.Groups...)
+		md.Properties = append(md.Properties, c.Properties...)
+		md.Hints = append(md.Hints, c.Hints...)
+	}
+
+	return md, nil
+}
+
+// Without returns the index without the entries of the jars at paths.
+func (j JarIndex) Without(paths []string) JarIndex {
+	removed := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		removed[p] = true
+	}
+
+	var kept JarIndex
+	for _, e := range j.Jars {
+		if !removed[e.Path] {
+			kept.Jars = append(kept.Jars, e)
+		}
+	}
+	return kept
+}
+
+// CachedJarIndexEntries reads the entries stored in the jar index layer by a previous build.
+func CachedJarIndexEntries(layer libcnb.Layer) map[string]JarIndexEntry {
+	if v, ok := layer.Metadata["version"].(string); !ok || v != jarIndexVersion {
+		return nil
+	}
+
+	digests, ok := layer.Metadata["jars"].(map[string]interface{})
+	if !ok || layer.Path == "" {
+		return nil
+	}
+
+	b, err := os.ReadFile(filepath.Join(layer.Path, jarIndexFile))
+	if err != nil {
+		return nil
+	}
+
+	var entries []JarIndexEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		// an unreadable cache is rebuilt
+		return nil
+	}
+
+	cached := make(map[string]JarIndexEntry, len(entries))
+	for _, e := range entries {
+		if _, ok := digests[e.SHA256]; ok {
+			cached[e.SHA256] = e
+		}
+	}
+	return cached
+}
+
+// JarIndexCache stores a jar index in a cache layer. The layer metadata only summarizes the indexed jars by digest,
+// the entries themselves are written to a file in the layer.
+type JarIndexCache struct {
+	Index JarIndex
+}
+
+func (j JarIndexCache) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	if err := os.MkdirAll(layer.Path, 0755); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to create %s\n%w", layer.Path, err)
+	}
+
+	b, err := json.Marshal(j.Index.Jars)
+	if err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to encode jar index\n%w", err)
+	}
+
+	file := filepath.Join(layer.Path, jarIndexFile)
+	if err := os.WriteFile(file, b, 0644); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to write %s\n%w", file, err)
+	}
+
+	digests := make(map[string]interface{}, len(j.Index.Jars))
+	for _, e := range j.Index.Jars {
+		digests[e.SHA256] = filepath.Base(e.Path)
+	}
+
+	layer.LayerTypes = libcnb.LayerTypes{Cache: true}
+	layer.Metadata = map[string]interface{}{"version": jarIndexVersion, "jars": digests}
+	return layer, nil
+}
+
+func (JarIndexCache) Name() string {
+	return JarIndexLayer
+}
+
+func indexJar(jar string, cached map[string]JarIndexEntry) (JarIndexEntry, error) {
+	digest, err := sha256File(jar)
+	if err != nil {
+		return JarIndexEntry{}, err
+	}
+
+	if e, ok := cached[digest]; ok {
+		e.Path = jar
+		return e, nil
+	}
+
+	in, err := zip.OpenReader(jar)
+	if err != nil {
+		return JarIndexEntry{}, fmt.Errorf("unable to open %s\n%w", jar, err)
+	}
+	defer in.Close()
+
+	e := JarIndexEntry{Path: jar, SHA256: digest}
+	seen := make(map[string]bool)
+	references := make(map[string]bool)
+	versions := make(map[int]ClassVersion)
+	multiRelease := false
+
+	for _, f := range in.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		if path.Ext(f.Name) != ".class" {
+			e.Resources = append(e.Resources, f.Name)
+
+			switch {
+			case f.Name == configurationMetadataEntry:
+				b, err := readZipFile(f)
+				if err != nil {
+					return JarIndexEntry{}, fmt.Errorf("unable to read %s in %s\n%w", f.Name, jar, err)
+				}
+				e.ConfigurationMetadata = string(b)
+			case pomPropertiesEntry.MatchString(f.Name):
+				b, err := readZipFile(f)
+				if err != nil {
+					return JarIndexEntry{}, fmt.Errorf("unable to read %s in %s\n%w", f.Name, jar, err)
+				}
+				if c, ok := pomPropertiesCoordinates(b); ok {
+					e.Coordinates = append(e.Coordinates, c)
+				}
+			case f.Name == "META-INF/MANIFEST.MF":
+				b, err := readZipFile(f)
+				if err != nil {
+					return JarIndexEntry{}, fmt.Errorf("unable to read %s in %s\n%w", f.Name, jar, err)
+				}
+				multiRelease = multiReleaseManifest.Match(b)
+				e.Licenses = appendUnique(e.Licenses, bundleLicenses(b)...)
+				for _, name := range identityManifestAttributes {
+					if v, ok := manifestAttribute(b, name); ok && v != "" {
+						if e.Manifest == nil {
+							e.Manifest = make(map[string]string)
+						}
+						e.Manifest[name] = v
+					}
+				}
+			case pomEntry.MatchString(f.Name):
+				b, err := readZipFile(f)
+				if err != nil {
+					return JarIndexEntry{}, fmt.Errorf("unable to read %s in %s\n%w", f.Name, jar, err)
+				}
+				e.Licenses = appendUnique(e.Licenses, pomLicenses(b)...)
+			case licenseFile.MatchString(f.Name):
+				b, err := readZipFile(f)
+				if err != nil {
+					return JarIndexEntry{}, fmt.Errorf("unable to read %s in %s\n%w", f.Name, jar, err)
+				}
+				if id, ok := licenseTextIdentifier(b); ok {
+					e.Licenses = appendUnique(e.Licenses, id)
+				}
+			}
+
+			continue
+		}
+
+		name, release := f.Name, 0
+		if m := multiReleaseVersionEntry.FindStringSubmatch(name); m != nil {
+			name = m[2]
+			release, _ = strconv.Atoi(m[1])
+		}
+
+		class := strings.ReplaceAll(strings.TrimSuffix(name, ".class"), "/", ".")
+		if !seen[class] {
+			seen[class] = true
+			e.Classes = append(e.Classes, class)
+		}
+
+		b, err := readZipFile(f)
+		if err != nil {
+			return JarIndexEntry{}, fmt.Errorf("unable to read %s in %s\n%w", f.Name, jar, err)
+		}
+		eeReferences(b, references)
+
+		if path.Base(name) == "module-info.class" {
+			continue
+		}
+		if major, err := ClassFileMajorVersion(bytes.NewReader(b)); err == nil && major > versions[release].Major {
+			versions[release] = ClassVersion{Release: release, Class: class, Major: major}
+		}
+	}
+
+	for r := range references {
+		e.EEReferences = append(e.EEReferences, r)
+	}
+	sort.Strings(e.EEReferences)
+
+	for release, v := range versions {
+		// versions are only loaded from multi-release jars
+		if release == 0 || multiRelease {
+			e.ClassVersions = append(e.ClassVersions, v)
+		}
+	}
+	sort.Slice(e.ClassVersions, func(i, j int) bool { return e.ClassVersions[i].Release < e.ClassVersions[j].Release })
+
+	return e, nil
+}
+
+func pomPropertiesCoordinates(b []byte) (MavenCoordinates, bool) {
+	l := properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}
+
+	p, err := l.LoadBytes(b)
+	if err != nil {
+		return MavenCoordinates{}, false
+	}
+
+	c := MavenCoordinates{
+		GroupID:    p.GetString("groupId", ""),
+		ArtifactID: p.GetString("artifactId", ""),
+		Version:    p.GetString("version", ""),
+	}
+	return c, c.GroupID != "" && c.ArtifactID != "" && c.Version != ""
+}
+
+// bundleLicenses returns the licenses of a Bundle-License manifest entry, such as
+// Apache-2.0;link="https://www.apache.org/licenses/LICENSE-2.0.txt", MIT.
+func bundleLicenses(manifest []byte) []string {
+	value, ok := manifestAttribute(manifest, "Bundle-License")
+	if !ok {
+		return nil
+	}
+
+	var licenses []string
+	inQuotes, start := false, 0
+	for i := 0; i <= len(value); i++ {
+		if i < len(value) && value[i] == '"' {
+			inQuotes = !inQuotes
+		}
+		if i < len(value) && (value[i] != ',' || inQuotes) {
+			continue
+		}
+
+		l := strings.SplitN(value[start:i], ";", 2)[0]
+		if l = strings.Trim(strings.TrimSpace(l), `"`); l != "" {
+			licenses = append(licenses, l)
+		}
+		start = i + 1
+	}
+
+	return licenses
+}
+
+// manifestAttribute returns a main attribute of a jar manifest, joining continuation lines.
+func manifestAttribute(manifest []byte, name string) (string, bool) {
+	unfolded := strings.NewReplacer("\r\n ", "", "\n ", "").Replace(string(manifest))
+
+	for _, line := range strings.Split(unfolded, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			// the main section ends at the first blank line
+			return "", false
+		}
+
+		if k, v, ok := strings.Cut(line, ":"); ok && strings.EqualFold(k, name) {
+			return strings.TrimSpace(v), true
+		}
+	}
+
+	return "", false
+}
+
+// pomLicenses returns the names, or URLs if unnamed, of the licenses declared by a Maven POM.
+func pomLicenses(pom []byte) []string {
+	var project struct {
+		Licenses []struct {
+			Name string `xml:"name"`
+			URL  string `xml:"url"`
+		} `xml:"licenses>license"`
+	}
+
+	if err := xml.Unmarshal(pom, &project); err != nil {
+		return nil
+	}
+
+	var licenses []string
+	for _, l := range project.Licenses {
+		if n := strings.TrimSpace(l.Name); n != "" {
+			licenses = append(licenses, n)
+		} else if u := strings.TrimSpace(l.URL); u != "" {
+			licenses = append(licenses, u)
+		}
+	}
+
+	return licenses
+}
+
+func appendUnique(s []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, e := range s {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			s = append(s, v)
+		}
+	}
+	return s
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	in, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	return io.ReadAll(in)
+}
+
+func sha256File(file string) (string, error) {
+	in, err := os.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to open %s\n%w", file, err)
+	}
+	defer in.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, in); err != nil {
+		return "", fmt.Errorf("unable to hash %s\n%w", file, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
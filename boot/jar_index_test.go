@@ -0,0 +1,198 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testJarIndex(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = os.MkdirTemp("", "jar-index")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeJar(t, filepath.Join(path, "demo-1.0.0.jar"), map[string]string{
+			"com/example/Demo.class":                         "",
+			"META-INF/versions/17/com/example/Demo.class":    "",
+			"META-INF/versions/21/com/example/Virtual.class": "",
+			"META-INF/MANIFEST.MF":                           "Manifest-Version: 1.0\nBundle-License: Apache-2.0;link=\"https://www.apache.org/lic\n enses/LICENSE-2.0.txt\", MIT\n",
+			"META-INF/maven/com.example/demo/pom.xml":        "<project><licenses><license><name>MIT</name></license><license><url>https://example.com/license</url></license></licenses></project>",
+			"META-INF/maven/com.example/demo/pom.properties": "groupId=com.example\nartifactId=demo\nversion=1.0.0\n",
+			"META-INF/spring-configuration-metadata.json":    `{"properties":[{"name":"demo.enabled","type":"java.lang.Boolean"}]}`,
+		})
+		writeJar(t, filepath.Join(path, "other-2.0.0.jar"), map[string]string{
+			"org/example/Other.class": "",
+			"META-INF/LICENSE.txt":    "Eclipse Public License - v 2.0\n\nTHE ACCOMPANYING PROGRAM IS PROVIDED UNDER THE TERMS OF THIS ECLIPSE PUBLIC LICENSE",
+		})
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	it("indexes jars", func() {
+		index, err := boot.NewJarIndexFromDirectory(path, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(index.Jars).To(HaveLen(2))
+		Expect(index.Jars[0].Path).To(Equal(filepath.Join(path, "demo-1.0.0.jar")))
+		Expect(index.Jars[0].SHA256).To(HaveLen(64))
+		Expect(index.Jars[0].Classes).To(ConsistOf("com.example.Demo", "com.example.Virtual"))
+		Expect(index.Jars[0].Resources).To(ConsistOf(
+			"META-INF/MANIFEST.MF",
+			"META-INF/maven/com.example/demo/pom.properties",
+			"META-INF/maven/com.example/demo/pom.xml",
+			"META-INF/spring-configuration-metadata.json",
+		))
+		Expect(index.Jars[0].Licenses).To(ConsistOf("Apache-2.0", "MIT", "https://example.com/license"))
+		Expect(index.Jars[0].Coordinates).To(Equal([]boot.MavenCoordinates{
+			{GroupID: "com.example", ArtifactID: "demo", Version: "1.0.0"},
+		}))
+		Expect(index.Jars[1].Licenses).To(Equal([]string{"EPL-2.0"}))
+
+		Expect(index.Classes()).To(HaveLen(3))
+		Expect(index.Classes()).To(HaveKey("org.example.Other"))
+
+		md, err := index.ConfigurationMetadata()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(md.Properties).To(Equal([]boot.Property{{Name: "demo.enabled", Type: "java.lang.Boolean"}}))
+	})
+
+	it("records the highest class file versions", func() {
+		writeJar(t, filepath.Join(path, "versions-1.0.0.jar"), map[string]string{
+			"META-INF/MANIFEST.MF":                     "Manifest-Version: 1.0\nMulti-Release: true\n",
+			"com/example/A.class":                      string(classFile(52)),
+			"com/example/B.class":                      string(classFile(55)),
+			"META-INF/versions/21/com/example/A.class": string(classFile(65)),
+			"META-INF/versions/21/module-info.class":   string(classFile(69)),
+		})
+
+		index, err := boot.NewJarIndex([]string{filepath.Join(path, "versions-1.0.0.jar")}, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(index.Jars[0].ClassVersions).To(Equal([]boot.ClassVersion{
+			{Class: "com.example.B", Major: 55},
+			{Release: 21, Class: "com.example.A", Major: 65},
+		}))
+		Expect(index.Jars[0].BytecodeTarget(17).Java).To(Equal(11))
+		Expect(index.Jars[0].BytecodeTarget(21).Java).To(Equal(21))
+	})
+
+	it("reuses cached entries with the same digest", func() {
+		index, err := boot.NewJarIndexFromDirectory(path, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		e := index.Jars[1]
+		e.Classes = []string{"cached.Class"}
+		cached := map[string]boot.JarIndexEntry{e.SHA256: e}
+
+		index, err = boot.NewJarIndexFromDirectory(path, cached)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(index.Jars[1].Classes).To(Equal([]string{"cached.Class"}))
+		Expect(index.Jars[0].Classes).To(ConsistOf("com.example.Demo", "com.example.Virtual"))
+	})
+
+	it("stores entries in the layer and summarizes them in layer metadata", func() {
+		index, err := boot.NewJarIndexFromDirectory(path, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err := boot.JarIndexCache{Index: index}.Contribute(libcnb.Layer{Path: filepath.Join(path, "layer")})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LayerTypes).To(Equal(libcnb.LayerTypes{Cache: true}))
+		Expect(layer.Metadata["jars"]).To(Equal(map[string]interface{}{
+			index.Jars[0].SHA256: filepath.Base(index.Jars[0].Path),
+			index.Jars[1].SHA256: filepath.Base(index.Jars[1].Path),
+		}))
+		Expect(filepath.Join(path, "layer", "jar-index.json")).To(BeARegularFile())
+		Expect(boot.CachedJarIndexEntries(layer)).To(Equal(map[string]boot.JarIndexEntry{
+			index.Jars[0].SHA256: index.Jars[0],
+			index.Jars[1].SHA256: index.Jars[1],
+		}))
+	})
+
+	it("ignores entries missing from the layer metadata", func() {
+		index, err := boot.NewJarIndexFromDirectory(path, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err := boot.JarIndexCache{Index: index}.Contribute(libcnb.Layer{Path: filepath.Join(path, "layer")})
+		Expect(err).NotTo(HaveOccurred())
+		delete(layer.Metadata["jars"].(map[string]interface{}), index.Jars[0].SHA256)
+
+		Expect(boot.CachedJarIndexEntries(layer)).To(Equal(map[string]boot.JarIndexEntry{index.Jars[1].SHA256: index.Jars[1]}))
+	})
+
+	it("ignores entries cached by another index version", func() {
+		index, err := boot.NewJarIndexFromDirectory(path, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err := boot.JarIndexCache{Index: index}.Contribute(libcnb.Layer{Path: filepath.Join(path, "layer")})
+		Expect(err).NotTo(HaveOccurred())
+		delete(layer.Metadata, "version")
+
+		Expect(boot.CachedJarIndexEntries(layer)).To(BeEmpty())
+	})
+
+	it("returns empty cache without layer metadata", func() {
+		Expect(boot.CachedJarIndexEntries(libcnb.Layer{})).To(BeEmpty())
+	})
+
+	it("returns an error for an invalid jar", func() {
+		Expect(os.WriteFile(filepath.Join(path, "invalid.jar"), []byte("not a jar"), 0644)).To(Succeed())
+
+		_, err := boot.NewJarIndexFromDirectory(path, nil)
+		Expect(err).To(MatchError(ContainSubstring("unable to open")))
+	})
+}
+
+func writeJar(t *testing.T, file string, entries map[string]string) {
+	t.Helper()
+	Expect := NewWithT(t).Expect
+
+	out, err := os.Create(file)
+	Expect(err).NotTo(HaveOccurred())
+	defer out.Close()
+
+	z := zip.NewWriter(out)
+	for name, content := range entries {
+		w, err := z.Create(name)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = w.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	Expect(z.Close()).To(Succeed())
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/pelletier/go-toml"
+)
+
+// DefaultAOTCacheJava is the Java release a training run creates an AOT cache with, rather than a CDS archive, when
+// the Spring Boot generation does not say otherwise.
+const DefaultAOTCacheJava = 25
+
+// JavaCompatibility is the range of Java releases a Spring Boot generation supports. A value of 0 means no constraint.
+type JavaCompatibility struct {
+	Name            string `toml:"Name"`
+	MinJava         int    `toml:"MinJava"`
+	MaxJava         int    `toml:"MaxJava"`
+	NativeMinJava   int    `toml:"NativeMinJava"`
+	CDSMinJava      int    `toml:"CDSMinJava"`
+	AOTCacheMinJava int    `toml:"AOTCacheMinJava"`
+}
+
+type javaCompatibilities struct {
+	Generations []JavaCompatibility `toml:"Generations"`
+}
+
+// JavaCompatibilityValidator checks the Java release of an image against the Spring Boot generation it runs.
+type JavaCompatibilityValidator struct {
+	Logger      bard.Logger
+	Generations []JavaCompatibility
+
+	// Fail fails the build on incompatibilities instead of warning. When unset, native image builds fail and JVM
+	// builds warn.
+	Fail *bool
+}
+
+func NewJavaCompatibilityValidator(path string) (JavaCompatibilityValidator, error) {
+	b, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return JavaCompatibilityValidator{}, fmt.Errorf("unable to read %s\n%w", path, err)
+	}
+
+	var c javaCompatibilities
+	if err := toml.Unmarshal(b, &c); err != nil {
+		return JavaCompatibilityValidator{}, fmt.Errorf("unable to decode %s\n%w", path, err)
+	}
+
+	return JavaCompatibilityValidator{Generations: c.Generations}, nil
+}
+
+// Generation returns the compatibility of the generation a Spring Boot version belongs to, or of the newest generation
+// for versions newer than all generations.
+func (v JavaCompatibilityValidator) Generation(version string) (JavaCompatibility, bool) {
+	nv := NormalizedVersion.FindString(version)
+	if nv == "" {
+		return JavaCompatibility{}, false
+	}
+
+	ver, err := semver.NewVersion(nv)
+	if err != nil {
+		return JavaCompatibility{}, false
+	}
+
+	var (
+		newest    JavaCompatibility
+		newestMin *semver.Version
+	)
+	for _, g := range v.Generations {
+		if c, err := semver.NewConstraint(g.Name); err == nil && c.Check(ver) {
+			return g, true
+		}
+		if min, err := semver.NewVersion(strings.TrimSuffix(g.Name, ".x")); err == nil && (newestMin == nil || min.GreaterThan(newestMin)) {
+			newest, newestMin = g, min
+		}
+	}
+
+	// versions released after the newest known generation are held to its requirements until they are listed
+	if newestMin != nil && ver.GreaterThan(newestMin) {
+		return newest, true
+	}
+
+	return JavaCompatibility{}, false
+}
+
+// AOTCacheJava returns the Java release a training run needs to create an AOT cache for a Spring Boot version.
+func (v JavaCompatibilityValidator) AOTCacheJava(version string) int {
+	if g, ok := v.Generation(version); ok && g.AOTCacheMinJava > 0 {
+		return g.AOTCacheMinJava
+	}
+	return DefaultAOTCacheJava
+}
+
+// Validate checks that java, determined from source, supports a Spring Boot version built as a native image or with a
+// performance optimization.
+func (v JavaCompatibilityValidator) Validate(version string, java int, source string, native bool, performanceType SpringPerformanceType) error {
+	g, ok := v.Generation(version)
+	if !ok || java <= 0 {
+		return nil
+	}
+
+	var problems []string
+	suggested := 0
+	require := func(min int, what string) {
+		if min > 0 && java < min {
+			problems = append(problems, fmt.Sprintf("%s needs Java %d or later", what, min))
+			if min > suggested {
+				suggested = min
+			}
+		}
+	}
+
+	if native {
+		require(g.NativeMinJava, fmt.Sprintf("a native image of Spring Boot %s", version))
+	} else {
+		require(g.MinJava, fmt.Sprintf("Spring Boot %s", version))
+		if g.MaxJava > 0 && java > g.MaxJava {
+			problems = append(problems, fmt.Sprintf("Spring Boot %s supports Java up to %d", version, g.MaxJava))
+			if suggested == 0 {
+				suggested = g.MaxJava
+			}
+		}
+	}
+
+	if performanceType == CdsAotCache {
+		require(g.CDSMinJava, fmt.Sprintf("a CDS archive of Spring Boot %s", version))
+		if len(problems) == 0 && g.AOTCacheMinJava > 0 && java < g.AOTCacheMinJava {
+			v.Logger.Bodyf("Java %d creates a CDS archive, set BP_JVM_VERSION=%d to create an AOT cache instead", java, g.AOTCacheMinJava)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf("the Java version %d from %s is not supported: %s; set BP_JVM_VERSION=%d to select a supported Java version",
+		java, source, strings.Join(problems, " and "), suggested)
+
+	fail := native
+	if v.Fail != nil {
+		fail = *v.Fail
+	}
+	if fail {
+		return fmt.Errorf("%s", message)
+	}
+
+	v.Logger.Header(Warningf("WARNING: %s", message))
+	return nil
+}
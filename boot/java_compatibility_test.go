@@ -0,0 +1,108 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testJavaCompatibility(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		b  *bytes.Buffer
+		jc boot.JavaCompatibilityValidator
+	)
+
+	it.Before(func() {
+		var err error
+
+		b = bytes.NewBuffer(nil)
+
+		jc, err = boot.NewJavaCompatibilityValidator(filepath.Join("testdata", "test-spring-boot-java-compatibility.toml"))
+		Expect(err).NotTo(HaveOccurred())
+		jc.Logger = bard.NewLogger(b)
+	})
+
+	it("ignores missing file", func() {
+		jc, err := boot.NewJavaCompatibilityValidator(filepath.Join("testdata", "unknown-spring-boot-java-compatibility.toml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(jc.Generations).To(BeEmpty())
+	})
+
+	it("ignores unknown generations and Java versions", func() {
+		Expect(jc.Validate("1.5.22", 8, "the JRE", false, boot.Without)).To(Succeed())
+		Expect(jc.Validate("3.1.0", 8, "the JRE", false, boot.Without)).To(Succeed())
+		Expect(jc.Validate("3.3.1", 0, "", false, boot.Without)).To(Succeed())
+		Expect(b.Len()).To(BeZero())
+	})
+
+	it("accepts supported Java versions", func() {
+		Expect(jc.Validate("3.3.1", 21, "the JRE", false, boot.Without)).To(Succeed())
+		Expect(b.Len()).To(BeZero())
+	})
+
+	it("warns about JVM applications by default", func() {
+		Expect(jc.Validate("3.3.1", 11, "$BP_JVM_VERSION", false, boot.Without)).To(Succeed())
+		Expect(b.String()).To(ContainSubstring("the Java version 11 from $BP_JVM_VERSION is not supported: Spring Boot 3.3.1 needs Java 17 or later; set BP_JVM_VERSION=17"))
+
+		b.Reset()
+		Expect(jc.Validate("2.7.18", 25, "the JRE", false, boot.Without)).To(Succeed())
+		Expect(b.String()).To(ContainSubstring("Spring Boot 2.7.18 supports Java up to 21; set BP_JVM_VERSION=21"))
+	})
+
+	it("fails native images by default", func() {
+		err := jc.Validate("4.0.0", 21, "the JRE", true, boot.Without)
+		Expect(err).To(MatchError("the Java version 21 from the JRE is not supported: a native image of Spring Boot 4.0.0 needs Java 25 or later; set BP_JVM_VERSION=25 to select a supported Java version"))
+	})
+
+	it("holds versions newer than all generations to the newest generation", func() {
+		err := jc.Validate("4.1.0", 21, "the JRE", true, boot.Without)
+		Expect(err).To(MatchError("the Java version 21 from the JRE is not supported: a native image of Spring Boot 4.1.0 needs Java 25 or later; set BP_JVM_VERSION=25 to select a supported Java version"))
+
+		Expect(jc.Validate("5.0.0", 25, "the JRE", true, boot.Without)).To(Succeed())
+	})
+
+	it("fails or warns as configured", func() {
+		fail := true
+		jc.Fail = &fail
+		Expect(jc.Validate("3.3.1", 11, "the JRE", false, boot.Without)).NotTo(Succeed())
+
+		fail = false
+		Expect(jc.Validate("4.0.0", 21, "the JRE", true, boot.Without)).To(Succeed())
+		Expect(b.String()).To(ContainSubstring("needs Java 25 or later"))
+	})
+
+	it("explains when a training run creates a CDS archive instead of an AOT cache", func() {
+		Expect(jc.Validate("3.3.1", 21, "the JRE", false, boot.CdsAotCache)).To(Succeed())
+		Expect(b.String()).To(ContainSubstring("Java 21 creates a CDS archive, set BP_JVM_VERSION=24 to create an AOT cache instead"))
+	})
+
+	it("returns the Java version creating an AOT cache", func() {
+		Expect(jc.AOTCacheJava("3.3.1")).To(Equal(24))
+		Expect(jc.AOTCacheJava("4.0.0")).To(Equal(boot.DefaultAOTCacheJava))
+		Expect(jc.AOTCacheJava("5.0.0")).To(Equal(boot.DefaultAOTCacheJava))
+	})
+}
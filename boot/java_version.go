@@ -3,6 +3,7 @@ package boot
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -46,6 +47,17 @@ func JavaMajorVersionFromJRE(executor effect.Executor) (int, error) {
 	return major, nil
 }
 
+// JREAvailable returns whether a JRE has been contributed to the build, in $JRE_HOME or $JAVA_HOME.
+func JREAvailable() bool {
+	home := sherpa.GetEnvWithDefault("JRE_HOME", sherpa.GetEnvWithDefault("JAVA_HOME", ""))
+	if home == "" {
+		return false
+	}
+
+	_, err := os.Stat(filepath.Join(home, "bin", "java"))
+	return err == nil
+}
+
 func JavaCommand() string {
 	javaCommand := "java"
 	if jreHome := sherpa.GetEnvWithDefault("JRE_HOME", sherpa.GetEnvWithDefault("JAVA_HOME", "")); jreHome != "" {
@@ -0,0 +1,348 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// spdxAliases maps the names and URLs jars commonly declare their licenses with to SPDX identifiers. Keys are
+// normalized by normalizeLicenseName.
+var spdxAliases = map[string]string{
+	"apache 2":                                "Apache-2.0",
+	"apache 2.0":                              "Apache-2.0",
+	"apache license 2.0":                      "Apache-2.0",
+	"apache license version 2":                "Apache-2.0",
+	"apache license version 2.0":              "Apache-2.0",
+	"apache software license version 2.0":     "Apache-2.0",
+	"the apache license version 2.0":          "Apache-2.0",
+	"the apache software license version 2.0": "Apache-2.0",
+	"asl 2.0":                                 "Apache-2.0",
+	"apache.org/licenses/license-2.0":         "Apache-2.0",
+	"apache.org/licenses/license-2.0.txt":     "Apache-2.0",
+	"apache.org/licenses/license-2.0.html":    "Apache-2.0",
+	"mit":                                     "MIT",
+	"mit license":                             "MIT",
+	"the mit license":                         "MIT",
+	"opensource.org/licenses/mit":             "MIT",
+	"opensource.org/licenses/mit-license.php": "MIT",
+	"bsd":                                         "BSD-3-Clause",
+	"bsd license":                                 "BSD-3-Clause",
+	"new bsd license":                             "BSD-3-Clause",
+	"bsd 3-clause":                                "BSD-3-Clause",
+	"bsd 3-clause license":                        "BSD-3-Clause",
+	"the bsd 3-clause license":                    "BSD-3-Clause",
+	"revised bsd":                                 "BSD-3-Clause",
+	"eclipse distribution license v. 1.0":         "BSD-3-Clause",
+	"eclipse distribution license - v 1.0":        "BSD-3-Clause",
+	"edl 1.0":                                     "BSD-3-Clause",
+	"opensource.org/licenses/bsd-3-clause":        "BSD-3-Clause",
+	"bsd 2-clause":                                "BSD-2-Clause",
+	"bsd 2-clause license":                        "BSD-2-Clause",
+	"simplified bsd license":                      "BSD-2-Clause",
+	"opensource.org/licenses/bsd-2-clause":        "BSD-2-Clause",
+	"eclipse public license 1.0":                  "EPL-1.0",
+	"eclipse public license - v 1.0":              "EPL-1.0",
+	"eclipse public license v1.0":                 "EPL-1.0",
+	"eclipse.org/legal/epl-v10.html":              "EPL-1.0",
+	"eclipse public license 2.0":                  "EPL-2.0",
+	"eclipse public license - v 2.0":              "EPL-2.0",
+	"eclipse public license v. 2.0":               "EPL-2.0",
+	"eclipse public license v2.0":                 "EPL-2.0",
+	"eclipse.org/legal/epl-2.0":                   "EPL-2.0",
+	"mozilla public license 2.0":                  "MPL-2.0",
+	"mozilla public license version 2.0":          "MPL-2.0",
+	"mozilla.org/mpl/2.0":                         "MPL-2.0",
+	"cddl 1.0":                                    "CDDL-1.0",
+	"cddl 1.1":                                    "CDDL-1.1",
+	"cddl+gpl license":                            "CDDL-1.1",
+	"cddl + gplv2 with classpath exception":       "CDDL-1.1",
+	"common development and distribution license": "CDDL-1.0",
+	"gpl2 w/ cpe":                                 "GPL-2.0-only WITH Classpath-exception-2.0",
+	"gnu general public license version 2 with the classpath exception": "GPL-2.0-only WITH Classpath-exception-2.0",
+	"gnu general public license v2.0 w/classpath exception":             "GPL-2.0-only WITH Classpath-exception-2.0",
+	"gnu general public license version 2":                              "GPL-2.0-only",
+	"gnu general public license v2.0":                                   "GPL-2.0-only",
+	"gpl v2":                                                            "GPL-2.0-only",
+	"gplv2":                                                             "GPL-2.0-only",
+	"gnu general public license version 3":                              "GPL-3.0-only",
+	"gnu general public license v3.0":                                   "GPL-3.0-only",
+	"gpl v3":                                                            "GPL-3.0-only",
+	"gplv3":                                                             "GPL-3.0-only",
+	"gnu lesser general public license":                                 "LGPL-2.1-only",
+	"gnu lesser general public license version 2.1":                     "LGPL-2.1-only",
+	"gnu lesser general public license v2.1":                            "LGPL-2.1-only",
+	"lgpl 2.1":                                                          "LGPL-2.1-only",
+	"lgpl v2.1":                                                         "LGPL-2.1-only",
+	"gnu lesser general public license version 3":                       "LGPL-3.0-only",
+	"gnu lesser general public license v3.0":                            "LGPL-3.0-only",
+	"lgpl v3":                                                           "LGPL-3.0-only",
+	"gnu affero general public license version 3":                       "AGPL-3.0-only",
+	"gnu affero general public license v3.0":                            "AGPL-3.0-only",
+	"agpl v3":                                                           "AGPL-3.0-only",
+	"agplv3":                                                            "AGPL-3.0-only",
+	"creative commons zero":                                             "CC0-1.0",
+	"cc0":                                                               "CC0-1.0",
+	"public domain, per creative commons cc0":                           "CC0-1.0",
+	"the unlicense":                                                     "Unlicense",
+	"isc license":                                                       "ISC",
+}
+
+// spdxIdentifiers are the SPDX identifiers recognized as such, including deprecated ones that map to current ones.
+var spdxIdentifiers = map[string]string{
+	"0bsd":             "0BSD",
+	"agpl-3.0":         "AGPL-3.0-only",
+	"apache-1.1":       "Apache-1.1",
+	"apache-2.0":       "Apache-2.0",
+	"bsd-2-clause":     "BSD-2-Clause",
+	"bsd-3-clause":     "BSD-3-Clause",
+	"cc0-1.0":          "CC0-1.0",
+	"cddl-1.0":         "CDDL-1.0",
+	"cddl-1.1":         "CDDL-1.1",
+	"epl-1.0":          "EPL-1.0",
+	"epl-2.0":          "EPL-2.0",
+	"gpl-2.0":          "GPL-2.0-only",
+	"gpl-3.0":          "GPL-3.0-only",
+	"isc":              "ISC",
+	"lgpl-2.1":         "LGPL-2.1-only",
+	"lgpl-3.0":         "LGPL-3.0-only",
+	"mit":              "MIT",
+	"mit-0":            "MIT-0",
+	"mpl-1.1":          "MPL-1.1",
+	"mpl-2.0":          "MPL-2.0",
+	"unlicense":        "Unlicense",
+	"upl-1.0":          "UPL-1.0",
+	"zlib":             "Zlib",
+	"bsl-1.0":          "BSL-1.0",
+	"wtfpl":            "WTFPL",
+	"json":             "JSON",
+	"postgresql":       "PostgreSQL",
+	"w3c":              "W3C",
+	"sspl-1.0":         "SSPL-1.0",
+	"busl-1.1":         "BUSL-1.1",
+	"elastic-2.0":      "Elastic-2.0",
+	"cpl-1.0":          "CPL-1.0",
+	"eupl-1.2":         "EUPL-1.2",
+	"artistic-2.0":     "Artistic-2.0",
+	"python-2.0":       "Python-2.0",
+	"openssl":          "OpenSSL",
+	"unicode-3.0":      "Unicode-3.0",
+	"unicode-dfs-2016": "Unicode-DFS-2016",
+}
+
+var spdxSuffix = regexp.MustCompile(`(?i)^(.+)-(only|or-later)$`)
+
+// SPDXLicense returns the SPDX identifier of a license declared by name, URL or identifier, such as Apache-2.0 for
+// "The Apache Software License, Version 2.0".
+func SPDXLicense(license string) (string, bool) {
+	s := strings.ToLower(strings.TrimSpace(license))
+
+	if id, ok := spdxIdentifiers[s]; ok {
+		return id, true
+	}
+	if m := spdxSuffix.FindStringSubmatch(s); m != nil {
+		if id, ok := spdxIdentifiers[m[1]]; ok {
+			return fmt.Sprintf("%s-%s", strings.TrimSuffix(id, "-only"), m[2]), true
+		}
+	}
+
+	if id, ok := spdxAliases[normalizeLicenseName(s)]; ok {
+		return id, true
+	}
+
+	return "", false
+}
+
+// NormalizeLicenses replaces licenses with their SPDX identifiers where known, keeping other licenses as declared, and
+// removes duplicates.
+func NormalizeLicenses(licenses []string) []string {
+	var normalized []string
+	for _, l := range licenses {
+		if id, ok := SPDXLicense(l); ok {
+			l = id
+		}
+		normalized = appendUnique(normalized, l)
+	}
+	return normalized
+}
+
+func normalizeLicenseName(s string) string {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "https://"), "http://")
+	s = strings.TrimPrefix(s, "www.")
+	s = strings.TrimSuffix(s, "/")
+	s = strings.NewReplacer(",", "", "(", "", ")", "").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// licenseTexts identify the license of META-INF/LICENSE files by phrases of their text, the first phrase being the title
+// of the license, most specific first.
+var licenseTexts = []struct {
+	ID      string
+	Phrases []string
+}{
+	{ID: "AGPL-3.0-only", Phrases: []string{"GNU AFFERO GENERAL PUBLIC LICENSE", "Version 3"}},
+	{ID: "LGPL-3.0-only", Phrases: []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 3"}},
+	{ID: "LGPL-2.1-only", Phrases: []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 2.1"}},
+	{ID: "GPL-2.0-only WITH Classpath-exception-2.0", Phrases: []string{"GNU GENERAL PUBLIC LICENSE", "Version 2", "CLASSPATH EXCEPTION"}},
+	{ID: "GPL-3.0-only", Phrases: []string{"GNU GENERAL PUBLIC LICENSE", "Version 3"}},
+	{ID: "GPL-2.0-only", Phrases: []string{"GNU GENERAL PUBLIC LICENSE", "Version 2"}},
+	{ID: "Apache-2.0", Phrases: []string{"Apache License", "Version 2.0"}},
+	{ID: "EPL-2.0", Phrases: []string{"Eclipse Public License - v 2.0"}},
+	{ID: "EPL-1.0", Phrases: []string{"Eclipse Public License - v 1.0"}},
+	{ID: "MPL-2.0", Phrases: []string{"Mozilla Public License Version 2.0"}},
+	{ID: "CDDL-1.1", Phrases: []string{"COMMON DEVELOPMENT AND DISTRIBUTION LICENSE", "Version 1.1"}},
+	{ID: "CDDL-1.0", Phrases: []string{"COMMON DEVELOPMENT AND DISTRIBUTION LICENSE"}},
+	{ID: "MIT", Phrases: []string{"Permission is hereby granted, free of charge"}},
+	{ID: "BSD-3-Clause", Phrases: []string{"Redistribution and use in source and binary forms", "Neither the name"}},
+	{ID: "BSD-2-Clause", Phrases: []string{"Redistribution and use in source and binary forms"}},
+}
+
+// licenseFile matches the license files of a jar, such as META-INF/LICENSE or META-INF/LICENSE.txt.
+var licenseFile = regexp.MustCompile(`^META-INF/(?i:licen[cs]e)([.-][^/]*)?$`)
+
+// licenseTextIdentifier returns the SPDX identifier of a license text, comparing case-insensitively and ignoring line
+// breaks. Licenses such as the MPL-2.0 and EPL-2.0 name others in their text, so the license whose title comes first
+// is returned, and the most specific one of those sharing a title.
+func licenseTextIdentifier(text []byte) (string, bool) {
+	normalized := bytes.ToUpper(bytes.Join(bytes.Fields(text), []byte(" ")))
+
+	id, position := "", -1
+	for _, l := range licenseTexts {
+		found := true
+		for _, p := range l.Phrases {
+			if !bytes.Contains(normalized, bytes.ToUpper([]byte(p))) {
+				found = false
+				break
+			}
+		}
+
+		if i := bytes.Index(normalized, bytes.ToUpper([]byte(l.Phrases[0]))); found && (position == -1 || i < position) {
+			id, position = l.ID, i
+		}
+	}
+
+	return id, position != -1
+}
+
+// DeniedLicense is a jar declaring only licenses of the deny-list.
+type DeniedLicense struct {
+	// Path is the path of the jar.
+	Path string
+
+	// Coordinates are the Maven coordinates of the jar, if known.
+	Coordinates []MavenCoordinates
+
+	// Licenses are the licenses the jar declares.
+	Licenses []string
+}
+
+func (d DeniedLicense) String() string {
+	name := filepath.Base(d.Path)
+	if len(d.Coordinates) > 0 {
+		c := d.Coordinates[0]
+		name = fmt.Sprintf("%s (%s:%s:%s)", name, c.GroupID, c.ArtifactID, c.Version)
+	}
+	return fmt.Sprintf("%s is licensed under %s", name, strings.Join(d.Licenses, ", "))
+}
+
+// FindDeniedLicenses returns the jars of index declaring only denied licenses. Licenses are compared by SPDX
+// identifier, a denied license matching its -only and -or-later variants as well as expressions it is part of, such as
+// GPL-2.0 matching GPL-2.0-only WITH Classpath-exception-2.0. Jars declaring several licenses may be used under any of
+// them, as Maven assumes, so they are reported only when all of them are denied.
+func FindDeniedLicenses(index JarIndex, denied []string) []DeniedLicense {
+	if len(denied) == 0 {
+		return nil
+	}
+
+	families := make([]string, 0, len(denied))
+	for _, d := range denied {
+		if id, ok := SPDXLicense(d); ok {
+			d = id
+		}
+		families = append(families, licenseFamily(d))
+	}
+
+	var found []DeniedLicense
+	for _, e := range index.Jars {
+		licenses := NormalizeLicenses(e.Licenses)
+		if len(licenses) > 0 && allDenied(licenses, families) {
+			found = append(found, DeniedLicense{Path: e.Path, Coordinates: e.Coordinates, Licenses: licenses})
+		}
+	}
+
+	return found
+}
+
+func allDenied(licenses []string, families []string) bool {
+	for _, l := range licenses {
+		denied := false
+		for _, f := range families {
+			if licenseFamily(strings.SplitN(l, " ", 2)[0]) == f {
+				denied = true
+				break
+			}
+		}
+		if !denied {
+			return false
+		}
+	}
+	return true
+}
+
+// licenseFamily returns an SPDX identifier without its -only or -or-later suffix, in lower case.
+func licenseFamily(id string) string {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if m := spdxSuffix.FindStringSubmatch(id); m != nil {
+		return m[1]
+	}
+	return id
+}
+
+// ReportDeniedLicenses logs the dependencies licensed under the comma separated denied licenses, and returns an error
+// instead with the fail policy.
+func ReportDeniedLicenses(logger bard.Logger, index JarIndex, denied string, policy string) error {
+	var licenses []string
+	for _, l := range strings.Split(denied, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			licenses = append(licenses, l)
+		}
+	}
+
+	found := FindDeniedLicenses(index, licenses)
+	if len(found) == 0 {
+		return nil
+	}
+
+	s := make([]string, len(found))
+	for i, d := range found {
+		s[i] = d.String()
+	}
+	message := fmt.Sprintf("Dependencies are licensed under licenses denied by $BP_SPRING_BOOT_DENIED_LICENSES:\n  %s", strings.Join(s, "\n  "))
+
+	if policy == "fail" {
+		return fmt.Errorf("%s", message)
+	}
+
+	logger.Header(Warningf("WARNING: %s", message))
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testLicense(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("normalizes licenses to SPDX identifiers", func() {
+		for declared, expected := range map[string]string{
+			"Apache-2.0": "Apache-2.0",
+			"apache-2.0": "Apache-2.0",
+			"The Apache Software License, Version 2.0":        "Apache-2.0",
+			"https://www.apache.org/licenses/LICENSE-2.0.txt": "Apache-2.0",
+			"MIT License":                    "MIT",
+			"Eclipse Public License - v 2.0": "EPL-2.0",
+			"GPL2 w/ CPE":                    "GPL-2.0-only WITH Classpath-exception-2.0",
+			"AGPL-3.0":                       "AGPL-3.0-only",
+			"GPL-3.0-or-later":               "GPL-3.0-or-later",
+			"GNU Lesser General Public License, Version 2.1": "LGPL-2.1-only",
+		} {
+			id, ok := boot.SPDXLicense(declared)
+			Expect(ok).To(BeTrue(), declared)
+			Expect(id).To(Equal(expected), declared)
+		}
+
+		_, ok := boot.SPDXLicense("Custom License")
+		Expect(ok).To(BeFalse())
+	})
+
+	it("keeps unknown licenses and removes duplicates", func() {
+		Expect(boot.NormalizeLicenses([]string{"Apache License, Version 2.0", "Custom License", "Apache-2.0"})).
+			To(Equal([]string{"Apache-2.0", "Custom License"}))
+	})
+
+	it("identifies license texts by their title", func() {
+		path := t.TempDir()
+		for _, id := range []string{"MPL-2.0", "EPL-2.0"} {
+			text, err := os.ReadFile(filepath.Join("testdata", "licenses", fmt.Sprintf("%s.txt", id)))
+			Expect(err).NotTo(HaveOccurred())
+
+			writeJar(t, filepath.Join(path, fmt.Sprintf("%s.jar", id)), map[string]string{"META-INF/LICENSE.txt": string(text)})
+		}
+
+		index, err := boot.NewJarIndexFromDirectory(path, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(index.Jars[0].Licenses).To(Equal([]string{"EPL-2.0"}))
+		Expect(index.Jars[1].Licenses).To(Equal([]string{"MPL-2.0"}))
+	})
+
+	context("FindDeniedLicenses", func() {
+		var index boot.JarIndex
+
+		it.Before(func() {
+			index = boot.JarIndex{Jars: []boot.JarIndexEntry{
+				{
+					Path:        "BOOT-INF/lib/agpl-1.0.0.jar",
+					Coordinates: []boot.MavenCoordinates{{GroupID: "com.example", ArtifactID: "agpl", Version: "1.0.0"}},
+					Licenses:    []string{"GNU Affero General Public License, Version 3"},
+				},
+				{Path: "BOOT-INF/lib/gpl-1.0.0.jar", Licenses: []string{"GPL-3.0-or-later"}},
+				{Path: "BOOT-INF/lib/jakarta-1.0.0.jar", Licenses: []string{"EPL-2.0", "GPL2 w/ CPE"}},
+				{Path: "BOOT-INF/lib/apache-1.0.0.jar", Licenses: []string{"Apache-2.0"}},
+			}}
+		})
+
+		it("finds jars declaring denied licenses", func() {
+			found := boot.FindDeniedLicenses(index, []string{"AGPL-3.0"})
+
+			Expect(found).To(HaveLen(1))
+			Expect(found[0].String()).To(Equal("agpl-1.0.0.jar (com.example:agpl:1.0.0) is licensed under AGPL-3.0-only"))
+		})
+
+		it("matches variants and expressions of denied licenses", func() {
+			found := boot.FindDeniedLicenses(index, []string{"GPL-3.0-only", "GPL-2.0", "EPL-2.0"})
+
+			Expect(found).To(HaveLen(2))
+			Expect(found[0].String()).To(Equal("gpl-1.0.0.jar is licensed under GPL-3.0-or-later"))
+			Expect(found[1].String()).To(Equal("jakarta-1.0.0.jar is licensed under EPL-2.0, GPL-2.0-only WITH Classpath-exception-2.0"))
+		})
+
+		it("does not find jars that may be used under a license that is not denied", func() {
+			found := boot.FindDeniedLicenses(index, []string{"GPL-2.0"})
+
+			Expect(found).To(BeEmpty())
+		})
+
+		it("finds nothing without deny-list", func() {
+			Expect(boot.FindDeniedLicenses(index, nil)).To(BeEmpty())
+		})
+
+		it("warns about a comma separated deny-list", func() {
+			out := &bytes.Buffer{}
+
+			Expect(boot.ReportDeniedLicenses(bard.NewLogger(out), index, "AGPL-3.0, SSPL-1.0", "warn")).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("agpl-1.0.0.jar (com.example:agpl:1.0.0) is licensed under AGPL-3.0-only"))
+		})
+
+		it("fails with the fail policy", func() {
+			err := boot.ReportDeniedLicenses(bard.NewLogger(&bytes.Buffer{}), index, "AGPL-3.0", "fail")
+			Expect(err).To(MatchError("Dependencies are licensed under licenses denied by $BP_SPRING_BOOT_DENIED_LICENSES:\n" +
+				"  agpl-1.0.0.jar (com.example:agpl:1.0.0) is licensed under AGPL-3.0-only"))
+		})
+	})
+}
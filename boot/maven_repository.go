@@ -0,0 +1,460 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var pomExpression = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// MavenCoordinates identifies an artifact in a Maven repository.
+type MavenCoordinates struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Classifier string
+	Type       string
+}
+
+// ParseMavenCoordinates parses coordinates in the groupId:artifactId[:type[:classifier]]:version form. The version may
+// be omitted, in which case it must be provided by dependency management.
+func ParseMavenCoordinates(s string) (MavenCoordinates, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+
+	switch len(parts) {
+	case 2:
+		return MavenCoordinates{GroupID: parts[0], ArtifactID: parts[1]}, nil
+	case 3:
+		return MavenCoordinates{GroupID: parts[0], ArtifactID: parts[1], Version: parts[2]}, nil
+	case 4:
+		return MavenCoordinates{GroupID: parts[0], ArtifactID: parts[1], Type: parts[2], Version: parts[3]}, nil
+	case 5:
+		return MavenCoordinates{GroupID: parts[0], ArtifactID: parts[1], Type: parts[2], Classifier: parts[3], Version: parts[4]}, nil
+	default:
+		return MavenCoordinates{}, fmt.Errorf("unable to parse Maven coordinates %q", s)
+	}
+}
+
+// Key returns the groupId:artifactId[:classifier] identity of the artifact, ignoring its version.
+func (m MavenCoordinates) Key() string {
+	if m.Classifier != "" {
+		return fmt.Sprintf("%s:%s:%s", m.GroupID, m.ArtifactID, m.Classifier)
+	}
+	return fmt.Sprintf("%s:%s", m.GroupID, m.ArtifactID)
+}
+
+func (m MavenCoordinates) String() string {
+	return fmt.Sprintf("%s:%s", m.Key(), m.Version)
+}
+
+// FileName returns the name of the artifact file in a Maven repository.
+func (m MavenCoordinates) FileName() string {
+	ext := m.Type
+	if ext == "" || ext == "bundle" || ext == "test-jar" {
+		ext = "jar"
+	}
+
+	if m.Classifier != "" {
+		return fmt.Sprintf("%s-%s-%s.%s", m.ArtifactID, m.Version, m.Classifier, ext)
+	}
+	return fmt.Sprintf("%s-%s.%s", m.ArtifactID, m.Version, ext)
+}
+
+// Path returns the path of the artifact file relative to the root of a Maven repository.
+func (m MavenCoordinates) Path() string {
+	return filepath.Join(strings.ReplaceAll(m.GroupID, ".", string(filepath.Separator)), m.ArtifactID, m.Version, m.FileName())
+}
+
+type pomExclusion struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+}
+
+type pomDependency struct {
+	GroupID    string         `xml:"groupId"`
+	ArtifactID string         `xml:"artifactId"`
+	Version    string         `xml:"version"`
+	Type       string         `xml:"type"`
+	Classifier string         `xml:"classifier"`
+	Scope      string         `xml:"scope"`
+	Optional   string         `xml:"optional"`
+	Exclusions []pomExclusion `xml:"exclusions>exclusion"`
+}
+
+type pomParent struct {
+	GroupID      string `xml:"groupId"`
+	ArtifactID   string `xml:"artifactId"`
+	Version      string `xml:"version"`
+	RelativePath string `xml:"relativePath"`
+}
+
+type pomProperties map[string]string
+
+func (p *pomProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*p = pomProperties{}
+
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch e := t.(type) {
+		case xml.StartElement:
+			var v string
+			if err := d.DecodeElement(&v, &e); err != nil {
+				return err
+			}
+			(*p)[e.Name.Local] = strings.TrimSpace(v)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// POM is the subset of a Maven project object model needed to resolve runtime dependencies.
+type POM struct {
+	GroupID              string          `xml:"groupId"`
+	ArtifactID           string          `xml:"artifactId"`
+	Version              string          `xml:"version"`
+	Packaging            string          `xml:"packaging"`
+	Parent               *pomParent      `xml:"parent"`
+	Properties           pomProperties   `xml:"properties"`
+	DependencyManagement []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+	Dependencies         []pomDependency `xml:"dependencies>dependency"`
+}
+
+// NewPOM decodes a POM from in.
+func NewPOM(in io.Reader) (POM, error) {
+	var p POM
+	if err := xml.NewDecoder(in).Decode(&p); err != nil {
+		return POM{}, err
+	}
+	return p, nil
+}
+
+// NewPOMFromPath decodes the POM at path.
+func NewPOMFromPath(path string) (POM, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return POM{}, fmt.Errorf("unable to open %s\n%w", path, err)
+	}
+	defer in.Close()
+
+	p, err := NewPOM(in)
+	if err != nil {
+		return POM{}, fmt.Errorf("unable to decode %s\n%w", path, err)
+	}
+	return p, nil
+}
+
+// effectivePOM is a POM merged with its parents and imported BOMs, with its expressions interpolated.
+type effectivePOM struct {
+	Coordinates  MavenCoordinates
+	Properties   map[string]string
+	Managed      map[string]pomDependency
+	Dependencies []pomDependency
+}
+
+func (e effectivePOM) interpolate(s string) string {
+	for i := 0; i < 10 && strings.Contains(s, "${"); i++ {
+		s = pomExpression.ReplaceAllStringFunc(s, func(m string) string {
+			key := m[2 : len(m)-1]
+			switch key {
+			case "project.groupId", "pom.groupId", "groupId":
+				return e.Coordinates.GroupID
+			case "project.artifactId", "pom.artifactId", "artifactId":
+				return e.Coordinates.ArtifactID
+			case "project.version", "pom.version", "version":
+				return e.Coordinates.Version
+			}
+			if v, ok := e.Properties[key]; ok {
+				return v
+			}
+			return m
+		})
+	}
+	return s
+}
+
+// MavenRepository resolves artifacts from a Maven repository laid out on the local filesystem.
+type MavenRepository struct {
+	Root string
+
+	poms map[string]effectivePOM
+}
+
+// NewMavenRepository creates a MavenRepository rooted at root.
+func NewMavenRepository(root string) *MavenRepository {
+	return &MavenRepository{Root: root, poms: map[string]effectivePOM{}}
+}
+
+// ArtifactPath returns the path of an artifact in the repository.
+func (r *MavenRepository) ArtifactPath(coordinates MavenCoordinates) string {
+	return filepath.Join(r.Root, coordinates.Path())
+}
+
+// Resolve walks the dependency graph of the roots, using the POMs in the repository, and returns the JAR artifacts
+// needed at runtime. Conflicts are settled the way Maven does, with the nearest declaration winning, and the versions
+// in managed take precedence over any transitive declaration.
+func (r *MavenRepository) Resolve(roots []MavenCoordinates, managed []MavenCoordinates) ([]MavenCoordinates, error) {
+	mgmt := map[string]string{}
+	for _, m := range managed {
+		if m.Type == "pom" {
+			e, err := r.effective(m)
+			if err != nil {
+				return nil, err
+			}
+			for k, d := range e.Managed {
+				if _, ok := mgmt[k]; !ok {
+					mgmt[k] = d.Version
+				}
+			}
+			continue
+		}
+		mgmt[m.Key()] = m.Version
+	}
+
+	type node struct {
+		coordinates MavenCoordinates
+		exclusions  map[string]bool
+	}
+
+	var queue []node
+	for _, c := range roots {
+		if v, ok := mgmt[c.Key()]; ok && c.Version == "" {
+			c.Version = v
+		}
+		queue = append(queue, node{coordinates: c, exclusions: map[string]bool{}})
+	}
+
+	var resolved []MavenCoordinates
+	seen := map[string]bool{}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		if seen[n.coordinates.Key()] {
+			continue
+		}
+		seen[n.coordinates.Key()] = true
+
+		if n.coordinates.Version == "" {
+			return nil, fmt.Errorf("unable to determine version of %s", n.coordinates.Key())
+		}
+
+		if n.coordinates.Type != "pom" {
+			resolved = append(resolved, n.coordinates)
+		}
+
+		e, err := r.effective(MavenCoordinates{GroupID: n.coordinates.GroupID, ArtifactID: n.coordinates.ArtifactID, Version: n.coordinates.Version})
+		if os.IsNotExist(err) {
+			// without a POM, the artifact is assumed to have no dependencies
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for _, d := range e.Dependencies {
+			if !isRuntimeScope(d.Scope) || d.Optional == "true" {
+				continue
+			}
+
+			c := MavenCoordinates{
+				GroupID:    d.GroupID,
+				ArtifactID: d.ArtifactID,
+				Version:    d.Version,
+				Classifier: d.Classifier,
+				Type:       d.Type,
+			}
+			if n.exclusions[c.GroupID+":"+c.ArtifactID] || n.exclusions[c.GroupID+":*"] || n.exclusions["*:*"] {
+				continue
+			}
+
+			if v, ok := mgmt[c.Key()]; ok {
+				c.Version = v
+			} else if c.Version == "" {
+				if m, ok := e.Managed[c.Key()]; ok {
+					c.Version = m.Version
+				}
+			}
+
+			exclusions := map[string]bool{}
+			for k := range n.exclusions {
+				exclusions[k] = true
+			}
+			for _, x := range d.Exclusions {
+				exclusions[x.GroupID+":"+x.ArtifactID] = true
+			}
+
+			queue = append(queue, node{coordinates: c, exclusions: exclusions})
+		}
+	}
+
+	return resolved, nil
+}
+
+// Effective returns the direct runtime dependencies and the dependency management of the POM at path, merged with
+// its parents found in the repository.
+func (r *MavenRepository) Effective(path string) ([]MavenCoordinates, []MavenCoordinates, error) {
+	p, err := NewPOMFromPath(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e, err := r.merge(p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dependencies, managed []MavenCoordinates
+	for _, d := range e.Dependencies {
+		if !isRuntimeScope(d.Scope) || d.Optional == "true" {
+			continue
+		}
+		c := MavenCoordinates{GroupID: d.GroupID, ArtifactID: d.ArtifactID, Version: d.Version, Classifier: d.Classifier, Type: d.Type}
+		if c.Version == "" {
+			c.Version = e.Managed[c.Key()].Version
+		}
+		dependencies = append(dependencies, c)
+	}
+	for _, d := range e.Managed {
+		managed = append(managed, MavenCoordinates{GroupID: d.GroupID, ArtifactID: d.ArtifactID, Version: d.Version, Classifier: d.Classifier})
+	}
+
+	return dependencies, managed, nil
+}
+
+func (r *MavenRepository) effective(coordinates MavenCoordinates) (effectivePOM, error) {
+	coordinates.Type, coordinates.Classifier = "pom", ""
+	if e, ok := r.poms[coordinates.String()]; ok {
+		return e, nil
+	}
+
+	file := r.ArtifactPath(coordinates)
+	if _, err := os.Stat(file); err != nil {
+		return effectivePOM{}, err
+	}
+
+	p, err := NewPOMFromPath(file)
+	if err != nil {
+		return effectivePOM{}, err
+	}
+
+	e, err := r.merge(p)
+	if err != nil {
+		return effectivePOM{}, fmt.Errorf("unable to resolve %s\n%w", coordinates, err)
+	}
+
+	r.poms[coordinates.String()] = e
+	return e, nil
+}
+
+func (r *MavenRepository) merge(p POM) (effectivePOM, error) {
+	e := effectivePOM{
+		Coordinates: MavenCoordinates{GroupID: p.GroupID, ArtifactID: p.ArtifactID, Version: p.Version},
+		Properties:  map[string]string{},
+		Managed:     map[string]pomDependency{},
+	}
+
+	var parent effectivePOM
+	if p.Parent != nil {
+		if e.Coordinates.GroupID == "" {
+			e.Coordinates.GroupID = p.Parent.GroupID
+		}
+		if e.Coordinates.Version == "" {
+			e.Coordinates.Version = p.Parent.Version
+		}
+
+		var err error
+		parent, err = r.effective(MavenCoordinates{GroupID: p.Parent.GroupID, ArtifactID: p.Parent.ArtifactID, Version: p.Parent.Version})
+		if err != nil && !os.IsNotExist(err) {
+			return effectivePOM{}, err
+		}
+		for k, v := range parent.Properties {
+			e.Properties[k] = v
+		}
+		e.Properties["project.parent.version"] = p.Parent.Version
+		e.Properties["project.parent.groupId"] = p.Parent.GroupID
+	}
+
+	for k, v := range p.Properties {
+		e.Properties[k] = v
+	}
+
+	// as Maven, direct entries win over inherited ones, which win over imported BOMs in declaration order
+	var imports []pomDependency
+	for _, d := range p.DependencyManagement {
+		d = e.interpolateDependency(d)
+
+		if d.Scope == "import" && d.Type == "pom" {
+			imports = append(imports, d)
+			continue
+		}
+
+		k := MavenCoordinates{GroupID: d.GroupID, ArtifactID: d.ArtifactID, Classifier: d.Classifier}.Key()
+		if _, ok := e.Managed[k]; !ok {
+			e.Managed[k] = d
+		}
+	}
+	for k, m := range parent.Managed {
+		if _, ok := e.Managed[k]; !ok {
+			e.Managed[k] = m
+		}
+	}
+	for _, d := range imports {
+		bom, err := r.effective(MavenCoordinates{GroupID: d.GroupID, ArtifactID: d.ArtifactID, Version: d.Version})
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return effectivePOM{}, err
+		}
+		for k, m := range bom.Managed {
+			if _, ok := e.Managed[k]; !ok {
+				e.Managed[k] = m
+			}
+		}
+	}
+
+	for _, d := range p.Dependencies {
+		e.Dependencies = append(e.Dependencies, e.interpolateDependency(d))
+	}
+	e.Dependencies = append(e.Dependencies, parent.Dependencies...)
+
+	return e, nil
+}
+
+func (e effectivePOM) interpolateDependency(d pomDependency) pomDependency {
+	d.GroupID = e.interpolate(d.GroupID)
+	d.ArtifactID = e.interpolate(d.ArtifactID)
+	d.Version = e.interpolate(d.Version)
+	d.Classifier = e.interpolate(d.Classifier)
+	d.Type = e.interpolate(d.Type)
+	d.Scope = e.interpolate(d.Scope)
+	return d
+}
+
+func isRuntimeScope(scope string) bool {
+	return scope == "" || scope == "compile" || scope == "runtime"
+}
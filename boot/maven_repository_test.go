@@ -0,0 +1,203 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testMavenRepository(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		root string
+	)
+
+	var POM = func(gav string, body string) {
+		c, err := boot.ParseMavenCoordinates(gav)
+		Expect(err).NotTo(HaveOccurred())
+		c.Type = "pom"
+
+		file := filepath.Join(root, c.Path())
+		Expect(os.MkdirAll(filepath.Dir(file), 0755)).To(Succeed())
+		Expect(os.WriteFile(file, []byte(fmt.Sprintf(`<project>
+<groupId>%s</groupId><artifactId>%s</artifactId><version>%s</version>
+%s
+</project>`, c.GroupID, c.ArtifactID, c.Version, body)), 0644)).To(Succeed())
+	}
+
+	var Dependency = func(gav string, extra string) string {
+		parts := strings.Split(gav, ":")
+		version := ""
+		if len(parts) > 2 {
+			version = fmt.Sprintf("<version>%s</version>", parts[2])
+		}
+		return fmt.Sprintf("<dependency><groupId>%s</groupId><artifactId>%s</artifactId>%s%s</dependency>", parts[0], parts[1], version, extra)
+	}
+
+	it.Before(func() {
+		var err error
+
+		root, err = os.MkdirTemp("", "maven-repository")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(root)).To(Succeed())
+	})
+
+	context("coordinates", func() {
+		it("parses coordinates", func() {
+			Expect(boot.ParseMavenCoordinates("g:a")).To(Equal(boot.MavenCoordinates{GroupID: "g", ArtifactID: "a"}))
+			Expect(boot.ParseMavenCoordinates("g:a:1")).To(Equal(boot.MavenCoordinates{GroupID: "g", ArtifactID: "a", Version: "1"}))
+			Expect(boot.ParseMavenCoordinates("g:a:jar:1")).To(Equal(boot.MavenCoordinates{GroupID: "g", ArtifactID: "a", Type: "jar", Version: "1"}))
+			Expect(boot.ParseMavenCoordinates("g:a:jar:c:1")).To(Equal(boot.MavenCoordinates{GroupID: "g", ArtifactID: "a", Type: "jar", Classifier: "c", Version: "1"}))
+
+			_, err := boot.ParseMavenCoordinates("g")
+			Expect(err).To(HaveOccurred())
+		})
+
+		it("creates repository paths", func() {
+			Expect(boot.MavenCoordinates{GroupID: "org.example", ArtifactID: "a", Version: "1"}.Path()).
+				To(Equal(filepath.Join("org", "example", "a", "1", "a-1.jar")))
+			Expect(boot.MavenCoordinates{GroupID: "org.example", ArtifactID: "a", Version: "1", Classifier: "c"}.Path()).
+				To(Equal(filepath.Join("org", "example", "a", "1", "a-1-c.jar")))
+		})
+	})
+
+	context("resolve", func() {
+		it("resolves transitive runtime dependencies", func() {
+			POM("g:a:1", "<dependencies>"+
+				Dependency("g:b:1", "")+
+				Dependency("g:test:1", "<scope>test</scope>")+
+				Dependency("g:optional:1", "<optional>true</optional>")+
+				"</dependencies>")
+			POM("g:b:1", "<dependencies>"+Dependency("g:c:1", "<scope>runtime</scope>")+"</dependencies>")
+
+			r := boot.NewMavenRepository(root)
+			Expect(r.Resolve([]boot.MavenCoordinates{{GroupID: "g", ArtifactID: "a", Version: "1"}}, nil)).To(Equal([]boot.MavenCoordinates{
+				{GroupID: "g", ArtifactID: "a", Version: "1"},
+				{GroupID: "g", ArtifactID: "b", Version: "1"},
+				{GroupID: "g", ArtifactID: "c", Version: "1"},
+			}))
+		})
+
+		it("prefers the nearest declaration", func() {
+			POM("g:a:1", "<dependencies>"+Dependency("g:b:1", "")+"</dependencies>")
+			POM("g:b:1", "<dependencies>"+Dependency("g:c:1", "")+"</dependencies>")
+
+			r := boot.NewMavenRepository(root)
+			Expect(r.Resolve([]boot.MavenCoordinates{
+				{GroupID: "g", ArtifactID: "a", Version: "1"},
+				{GroupID: "g", ArtifactID: "c", Version: "2"},
+			}, nil)).To(Equal([]boot.MavenCoordinates{
+				{GroupID: "g", ArtifactID: "a", Version: "1"},
+				{GroupID: "g", ArtifactID: "c", Version: "2"},
+				{GroupID: "g", ArtifactID: "b", Version: "1"},
+			}))
+		})
+
+		it("applies exclusions", func() {
+			POM("g:a:1", "<dependencies>"+
+				Dependency("g:b:1", "<exclusions><exclusion><groupId>g</groupId><artifactId>c</artifactId></exclusion></exclusions>")+
+				"</dependencies>")
+			POM("g:b:1", "<dependencies>"+Dependency("g:c:1", "")+"</dependencies>")
+
+			r := boot.NewMavenRepository(root)
+			Expect(r.Resolve([]boot.MavenCoordinates{{GroupID: "g", ArtifactID: "a", Version: "1"}}, nil)).To(Equal([]boot.MavenCoordinates{
+				{GroupID: "g", ArtifactID: "a", Version: "1"},
+				{GroupID: "g", ArtifactID: "b", Version: "1"},
+			}))
+		})
+
+		it("applies dependency management from parents and imported boms", func() {
+			POM("g:bom:1", "<dependencyManagement><dependencies>"+Dependency("g:c:3", "")+"</dependencies></dependencyManagement>")
+			POM("g:parent:1", "<properties><b.version>2</b.version></properties>"+
+				"<dependencyManagement><dependencies>"+
+				Dependency("g:b:${b.version}", "")+
+				Dependency("g:bom:1", "<type>pom</type><scope>import</scope>")+
+				"</dependencies></dependencyManagement>")
+			POM("g:a:1", "<parent><groupId>g</groupId><artifactId>parent</artifactId><version>1</version></parent>"+
+				"<dependencies>"+Dependency("g:b", "")+"</dependencies>")
+			POM("g:b:2", "<parent><groupId>g</groupId><artifactId>parent</artifactId><version>1</version></parent>"+
+				"<dependencies>"+Dependency("g:c", "")+"</dependencies>")
+
+			r := boot.NewMavenRepository(root)
+			Expect(r.Resolve([]boot.MavenCoordinates{{GroupID: "g", ArtifactID: "a", Version: "1"}}, nil)).To(Equal([]boot.MavenCoordinates{
+				{GroupID: "g", ArtifactID: "a", Version: "1"},
+				{GroupID: "g", ArtifactID: "b", Version: "2"},
+				{GroupID: "g", ArtifactID: "c", Version: "3"},
+			}))
+		})
+
+		it("prefers direct and inherited dependency management over imported boms", func() {
+			POM("g:bom:1", "<dependencyManagement><dependencies>"+
+				Dependency("g:b:9", "")+Dependency("g:c:9", "")+Dependency("g:d:1", "")+
+				"</dependencies></dependencyManagement>")
+			POM("g:other-bom:1", "<dependencyManagement><dependencies>"+Dependency("g:d:9", "")+"</dependencies></dependencyManagement>")
+			POM("g:parent:1", "<dependencyManagement><dependencies>"+Dependency("g:c:3", "")+"</dependencies></dependencyManagement>")
+			POM("g:a:1", "<parent><groupId>g</groupId><artifactId>parent</artifactId><version>1</version></parent>"+
+				"<dependencyManagement><dependencies>"+
+				Dependency("g:bom:1", "<type>pom</type><scope>import</scope>")+
+				Dependency("g:other-bom:1", "<type>pom</type><scope>import</scope>")+
+				Dependency("g:b:2", "")+
+				"</dependencies></dependencyManagement>"+
+				"<dependencies>"+Dependency("g:b", "")+Dependency("g:c", "")+Dependency("g:d", "")+"</dependencies>")
+			POM("g:b:2", "")
+			POM("g:c:3", "")
+			POM("g:d:1", "")
+
+			r := boot.NewMavenRepository(root)
+			Expect(r.Resolve([]boot.MavenCoordinates{{GroupID: "g", ArtifactID: "a", Version: "1"}}, nil)).To(Equal([]boot.MavenCoordinates{
+				{GroupID: "g", ArtifactID: "a", Version: "1"},
+				{GroupID: "g", ArtifactID: "b", Version: "2"},
+				{GroupID: "g", ArtifactID: "c", Version: "3"},
+				{GroupID: "g", ArtifactID: "d", Version: "1"},
+			}))
+		})
+
+		it("overrides transitive versions with managed versions", func() {
+			POM("g:a:1", "<dependencies>"+Dependency("g:b:1", "")+"</dependencies>")
+
+			r := boot.NewMavenRepository(root)
+			Expect(r.Resolve(
+				[]boot.MavenCoordinates{{GroupID: "g", ArtifactID: "a", Version: "1"}},
+				[]boot.MavenCoordinates{{GroupID: "g", ArtifactID: "b", Version: "5"}},
+			)).To(Equal([]boot.MavenCoordinates{
+				{GroupID: "g", ArtifactID: "a", Version: "1"},
+				{GroupID: "g", ArtifactID: "b", Version: "5"},
+			}))
+		})
+
+		it("fails when a version cannot be determined", func() {
+			POM("g:a:1", "<dependencies>"+Dependency("g:b", "")+"</dependencies>")
+
+			r := boot.NewMavenRepository(root)
+			_, err := r.Resolve([]boot.MavenCoordinates{{GroupID: "g", ArtifactID: "a", Version: "1"}}, nil)
+			Expect(err).To(MatchError("unable to determine version of g:b"))
+		})
+	})
+}
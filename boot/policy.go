@@ -0,0 +1,323 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/bindings"
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	// BindingTypePolicy is the type of the binding providing the build policy, as a policy.toml entry or a path entry
+	// pointing to the policy file.
+	BindingTypePolicy = "spring-boot-policy"
+
+	PolicyRuleMinBootVersion    = "min-boot-version"
+	PolicyRuleNoSnapshots       = "no-snapshots"
+	PolicyRuleForbiddenArtifact = "forbidden-artifact"
+	PolicyRuleRequireActuator   = "require-actuator"
+
+	PolicySeverityError   = "error"
+	PolicySeverityWarning = "warning"
+	PolicySeverityInfo    = "info"
+)
+
+// snapshotVersion matches Maven snapshot versions, both unresolved and timestamped.
+var snapshotVersion = regexp.MustCompile(`(?i)-SNAPSHOT$|-\d{8}\.\d{6}-\d+$`)
+
+// PolicyRule is a rule of the build policy.
+type PolicyRule struct {
+	// Type is the kind of rule, one of min-boot-version, no-snapshots, forbidden-artifact and require-actuator.
+	Type string `toml:"type"`
+
+	// Severity is error, failing the build, warning or info. Defaults to error.
+	Severity string `toml:"severity"`
+
+	// Description explains the rule in the report, instead of its type.
+	Description string `toml:"description"`
+
+	// Version is the minimum Spring Boot version of min-boot-version rules.
+	Version string `toml:"version"`
+
+	// Artifact is the artifact of forbidden-artifact rules, as group:artifact or artifact.
+	Artifact string `toml:"artifact"`
+
+	// Versions restricts forbidden-artifact rules to versions matching comma-separated constraints, such as < 2.17.1.
+	Versions string `toml:"versions"`
+}
+
+func (r PolicyRule) String() string {
+	if r.Description != "" {
+		return r.Description
+	}
+
+	switch r.Type {
+	case PolicyRuleMinBootVersion:
+		return fmt.Sprintf("%s %s", r.Type, r.Version)
+	case PolicyRuleForbiddenArtifact:
+		return strings.TrimSpace(fmt.Sprintf("%s %s %s", r.Type, r.Artifact, r.Versions))
+	default:
+		return r.Type
+	}
+}
+
+// Policy is a set of rules an application must satisfy to be built.
+type Policy struct {
+	Rules []PolicyRule `toml:"rules"`
+}
+
+// PolicyFacts are what the build learned about the application that rules are evaluated against.
+type PolicyFacts struct {
+	BootVersion  string
+	Dependencies []Dependency
+}
+
+// PolicyViolation is a rule the application does not satisfy.
+type PolicyViolation struct {
+	Rule    PolicyRule
+	Message string
+}
+
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("[%s] %s: %s", strings.ToUpper(v.Rule.Severity), v.Rule, v.Message)
+}
+
+// NewPolicy decodes and validates a policy in TOML format.
+func NewPolicy(b []byte) (Policy, error) {
+	var p Policy
+	if err := toml.Unmarshal(b, &p); err != nil {
+		return Policy{}, fmt.Errorf("unable to decode policy\n%w", err)
+	}
+
+	for i, r := range p.Rules {
+		switch r.Severity {
+		case "":
+			p.Rules[i].Severity = PolicySeverityError
+		case PolicySeverityError, PolicySeverityWarning, PolicySeverityInfo:
+		default:
+			return Policy{}, fmt.Errorf("invalid severity %s of rule %s, must be error, warning or info", r.Severity, r)
+		}
+
+		switch r.Type {
+		case PolicyRuleMinBootVersion:
+			if r.Version == "" {
+				return Policy{}, fmt.Errorf("rule %s requires a version", r.Type)
+			}
+		case PolicyRuleForbiddenArtifact:
+			if r.Artifact == "" {
+				return Policy{}, fmt.Errorf("rule %s requires an artifact", r.Type)
+			}
+			if _, err := ParseMavenVersionConstraints(r.Versions); err != nil {
+				return Policy{}, fmt.Errorf("invalid versions of rule %s\n%w", r, err)
+			}
+		case PolicyRuleNoSnapshots, PolicyRuleRequireActuator:
+		default:
+			return Policy{}, fmt.Errorf("unknown rule type %q, must be %s, %s, %s or %s", r.Type,
+				PolicyRuleMinBootVersion, PolicyRuleNoSnapshots, PolicyRuleForbiddenArtifact, PolicyRuleRequireActuator)
+		}
+	}
+
+	return p, nil
+}
+
+// ResolvePolicy returns the build policy. A binding of type spring-boot-policy takes precedence over path, the value
+// of BP_SPRING_BOOT_POLICY pointing to a policy file relative to the application root.
+func ResolvePolicy(binds libcnb.Bindings, appPath string, path string) (Policy, bool, error) {
+	if b, ok, err := bindings.ResolveOne(binds, bindings.OfType(BindingTypePolicy)); err != nil {
+		return Policy{}, false, fmt.Errorf("unable to resolve binding %s\n%w", BindingTypePolicy, err)
+	} else if ok {
+		if s, ok := b.Secret["policy.toml"]; ok {
+			p, err := NewPolicy([]byte(s))
+			return p, true, err
+		}
+		p, ok := b.Secret["path"]
+		if !ok {
+			return Policy{}, false, fmt.Errorf("binding %s must contain policy.toml or path", b.Name)
+		}
+		path = strings.TrimSpace(p)
+	} else if path = strings.TrimSpace(path); path == "" {
+		return Policy{}, false, nil
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(appPath, path)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, false, fmt.Errorf("unable to read policy %s\n%w", path, err)
+	}
+
+	p, err := NewPolicy(b)
+	if err != nil {
+		return Policy{}, false, fmt.Errorf("unable to load policy %s\n%w", path, err)
+	}
+
+	return p, true, nil
+}
+
+// Evaluate returns the violations of the rules of the policy, in the order of the rules.
+func (p Policy) Evaluate(facts PolicyFacts) []PolicyViolation {
+	var violations []PolicyViolation
+
+	for _, r := range p.Rules {
+		for _, m := range r.evaluate(facts) {
+			violations = append(violations, PolicyViolation{Rule: r, Message: m})
+		}
+	}
+
+	return violations
+}
+
+func (r PolicyRule) evaluate(facts PolicyFacts) []string {
+	switch r.Type {
+	case PolicyRuleMinBootVersion:
+		if facts.BootVersion != "" && CompareMavenVersions(facts.BootVersion, r.Version) < 0 {
+			return []string{fmt.Sprintf("Spring Boot %s is older than %s", facts.BootVersion, r.Version)}
+		}
+
+	case PolicyRuleNoSnapshots:
+		var messages []string
+		for _, d := range facts.Dependencies {
+			if snapshotVersion.MatchString(d.Version) {
+				messages = append(messages, fmt.Sprintf("%s is a snapshot", d))
+			}
+		}
+		return messages
+
+	case PolicyRuleForbiddenArtifact:
+		group, name, ok := strings.Cut(r.Artifact, ":")
+		if !ok {
+			group, name = "", r.Artifact
+		}
+		constraints, _ := ParseMavenVersionConstraints(r.Versions)
+
+		var messages []string
+		for _, d := range facts.Dependencies {
+			if d.Name != name || (group != "" && d.Group != group) {
+				continue
+			}
+			if constraints.Check(d.Version) {
+				messages = append(messages, fmt.Sprintf("%s is forbidden", d))
+			}
+		}
+		return messages
+
+	case PolicyRuleRequireActuator:
+		if !FindExistingDependency(facts.Dependencies, "spring-boot-actuator") {
+			return []string{"spring-boot-actuator is not a dependency"}
+		}
+	}
+
+	return nil
+}
+
+// MavenVersionConstraint compares versions to a version with an operator: <, <=, >, >=, = or !=.
+type MavenVersionConstraint struct {
+	Operator string
+	Version  string
+}
+
+// MavenVersionConstraints are constraints a version must all satisfy.
+type MavenVersionConstraints []MavenVersionConstraint
+
+var mavenVersionConstraint = regexp.MustCompile(`^(<=|>=|!=|<|>|=)?\s*(\S+)$`)
+
+// ParseMavenVersionConstraints parses comma-separated constraints, such as ">= 2.0, < 2.17.1". A version without
+// operator must be equal.
+func ParseMavenVersionConstraints(s string) (MavenVersionConstraints, error) {
+	var constraints MavenVersionConstraints
+
+	for _, c := range strings.Split(s, ",") {
+		if c = strings.TrimSpace(c); c == "" {
+			continue
+		}
+
+		m := mavenVersionConstraint.FindStringSubmatch(c)
+		if m == nil {
+			return nil, fmt.Errorf("invalid version constraint %s", c)
+		}
+
+		operator := m[1]
+		if operator == "" {
+			operator = "="
+		}
+		constraints = append(constraints, MavenVersionConstraint{Operator: operator, Version: m[2]})
+	}
+
+	return constraints, nil
+}
+
+// Check returns whether a version satisfies all constraints.
+func (c MavenVersionConstraints) Check(version string) bool {
+	for _, constraint := range c {
+		r := CompareMavenVersions(version, constraint.Version)
+
+		var ok bool
+		switch constraint.Operator {
+		case "<":
+			ok = r < 0
+		case "<=":
+			ok = r <= 0
+		case ">":
+			ok = r > 0
+		case ">=":
+			ok = r >= 0
+		case "=":
+			ok = r == 0
+		case "!=":
+			ok = r != 0
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ReportPolicyViolations logs the violations of a policy as a single report, and returns an error if any violates a
+// rule of severity error.
+func ReportPolicyViolations(logger bard.Logger, violations []PolicyViolation) error {
+	if len(violations) == 0 {
+		logger.Header("Policy satisfied")
+		return nil
+	}
+
+	var errors []string
+	logger.Header(Warningf("Policy violations:"))
+	for _, v := range violations {
+		logger.Bodyf("%s", v)
+		if v.Rule.Severity == PolicySeverityError {
+			errors = append(errors, v.String())
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("the application violates %d policy rules of severity error:\n  %s", len(errors), strings.Join(errors, "\n  "))
+	}
+
+	return nil
+}
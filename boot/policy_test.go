@@ -0,0 +1,184 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+const policyTOML = `
+[[rules]]
+type = "min-boot-version"
+version = "3.2.0"
+
+[[rules]]
+type = "no-snapshots"
+severity = "warning"
+
+[[rules]]
+type = "forbidden-artifact"
+artifact = "org.apache.logging.log4j:log4j-core"
+versions = "< 2.17.1"
+
+[[rules]]
+type = "forbidden-artifact"
+artifact = "spring-boot-devtools"
+description = "spring-boot-devtools must not be packaged"
+
+[[rules]]
+type = "require-actuator"
+severity = "info"
+`
+
+func testPolicy(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		policy boot.Policy
+	)
+
+	it.Before(func() {
+		var err error
+		policy, err = boot.NewPolicy([]byte(policyTOML))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it("evaluates rules", func() {
+		violations := policy.Evaluate(boot.PolicyFacts{
+			BootVersion: "3.1.5",
+			Dependencies: []boot.Dependency{
+				{Group: "org.apache.logging.log4j", Name: "log4j-core", Version: "2.14.1"},
+				{Group: "com.example", Name: "demo", Version: "1.0.0-SNAPSHOT"},
+				{Group: "com.example", Name: "other", Version: "1.0.0-20240102.030405-6"},
+				{Group: "org.springframework.boot", Name: "spring-boot-devtools", Version: "3.1.5"},
+			},
+		})
+
+		s := make([]string, len(violations))
+		for i, v := range violations {
+			s[i] = v.String()
+		}
+		Expect(s).To(Equal([]string{
+			"[ERROR] min-boot-version 3.2.0: Spring Boot 3.1.5 is older than 3.2.0",
+			"[WARNING] no-snapshots: com.example:demo:1.0.0-SNAPSHOT is a snapshot",
+			"[WARNING] no-snapshots: com.example:other:1.0.0-20240102.030405-6 is a snapshot",
+			"[ERROR] forbidden-artifact org.apache.logging.log4j:log4j-core < 2.17.1: org.apache.logging.log4j:log4j-core:2.14.1 is forbidden",
+			"[ERROR] spring-boot-devtools must not be packaged: org.springframework.boot:spring-boot-devtools:3.1.5 is forbidden",
+			"[INFO] require-actuator: spring-boot-actuator is not a dependency",
+		}))
+	})
+
+	it("is satisfied by compliant applications", func() {
+		Expect(policy.Evaluate(boot.PolicyFacts{
+			BootVersion: "3.3.1",
+			Dependencies: []boot.Dependency{
+				{Group: "org.apache.logging.log4j", Name: "log4j-core", Version: "2.17.1"},
+				{Group: "org.springframework.boot", Name: "spring-boot-actuator", Version: "3.3.1"},
+			},
+		})).To(BeEmpty())
+	})
+
+	it("rejects invalid rules", func() {
+		_, err := boot.NewPolicy([]byte("[[rules]]\ntype = \"unknown\"\n"))
+		Expect(err).To(MatchError(ContainSubstring(`unknown rule type "unknown"`)))
+
+		_, err = boot.NewPolicy([]byte("[[rules]]\ntype = \"no-snapshots\"\nseverity = \"fatal\"\n"))
+		Expect(err).To(MatchError("invalid severity fatal of rule no-snapshots, must be error, warning or info"))
+
+		_, err = boot.NewPolicy([]byte("[[rules]]\ntype = \"forbidden-artifact\"\n"))
+		Expect(err).To(MatchError("rule forbidden-artifact requires an artifact"))
+	})
+
+	it("checks version constraints", func() {
+		c, err := boot.ParseMavenVersionConstraints(">= 2.0, < 2.17.1")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.Check("1.2.17")).To(BeFalse())
+		Expect(c.Check("2.14.1")).To(BeTrue())
+		Expect(c.Check("2.17.1")).To(BeFalse())
+
+		c, err = boot.ParseMavenVersionConstraints("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Check("1.0.0")).To(BeTrue())
+
+		_, err = boot.ParseMavenVersionConstraints("< 1 2")
+		Expect(err).To(MatchError("invalid version constraint < 1 2"))
+	})
+
+	it("reports violations and fails on errors", func() {
+		out := &bytes.Buffer{}
+		violations := policy.Evaluate(boot.PolicyFacts{BootVersion: "3.1.5"})
+
+		err := boot.ReportPolicyViolations(bard.NewLogger(out), violations)
+		Expect(err).To(MatchError("the application violates 1 policy rules of severity error:\n  [ERROR] min-boot-version 3.2.0: Spring Boot 3.1.5 is older than 3.2.0"))
+		Expect(out.String()).To(ContainSubstring("[INFO] require-actuator: spring-boot-actuator is not a dependency"))
+	})
+
+	it("does not fail on warnings", func() {
+		violations := policy.Evaluate(boot.PolicyFacts{
+			BootVersion:  "3.3.1",
+			Dependencies: []boot.Dependency{{Name: "demo", Version: "1.0.0-SNAPSHOT"}},
+		})
+
+		Expect(violations).To(HaveLen(2))
+		Expect(boot.ReportPolicyViolations(bard.NewLogger(&bytes.Buffer{}), violations)).To(Succeed())
+	})
+
+	context("ResolvePolicy", func() {
+		var appPath string
+
+		it.Before(func() {
+			appPath = t.TempDir()
+			Expect(os.WriteFile(filepath.Join(appPath, "policy.toml"), []byte(policyTOML), 0644)).To(Succeed())
+		})
+
+		it("reads the policy of a binding", func() {
+			binds := libcnb.Bindings{libcnb.NewBinding("policy", "/bindings/policy", map[string]string{
+				"type":        "spring-boot-policy",
+				"policy.toml": "[[rules]]\ntype = \"no-snapshots\"\n",
+			})}
+
+			p, ok, err := boot.ResolvePolicy(binds, appPath, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(p.Rules).To(Equal([]boot.PolicyRule{{Type: "no-snapshots", Severity: "error"}}))
+		})
+
+		it("reads the path relative to the application", func() {
+			p, ok, err := boot.ResolvePolicy(nil, appPath, "policy.toml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(p.Rules).To(HaveLen(5))
+		})
+
+		it("has no policy by default", func() {
+			_, ok, err := boot.ResolvePolicy(nil, appPath, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
+)
+
+const (
+	LabelImageRevision    = "org.opencontainers.image.revision"
+	LabelImageSource      = "org.opencontainers.image.source"
+	LabelImageCreated     = "org.opencontainers.image.created"
+	LabelImageVendor      = "org.opencontainers.image.vendor"
+	LabelImageDescription = "org.opencontainers.image.description"
+	LabelBuildGroup       = "org.springframework.boot.build.group"
+	LabelBuildArtifact    = "org.springframework.boot.build.artifact"
+	LabelBuildTime        = "org.springframework.boot.build.time"
+)
+
+var buildTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z0700",
+	"2006-01-02T15:04:05Z0700",
+}
+
+// Provenance contributes image labels describing where an application was built from. It reads git.properties,
+// generated by git-commit-id-maven-plugin or gradle-git-properties, and META-INF/build-info.properties, generated by
+// the build-info goal of the Spring Boot build plugins.
+type Provenance struct {
+	ApplicationPath   string
+	Classes           string
+	GitDisabled       bool
+	BuildInfoDisabled bool
+}
+
+// Labels returns the provenance labels of the application.
+func (p Provenance) Labels() ([]libcnb.Label, error) {
+	var labels []libcnb.Label
+
+	var git, buildInfo *properties.Properties
+	var err error
+
+	if !p.GitDisabled {
+		if git, err = p.load("git.properties"); err != nil {
+			return nil, err
+		}
+	}
+	if !p.BuildInfoDisabled {
+		if buildInfo, err = p.load(filepath.Join("META-INF", "build-info.properties")); err != nil {
+			return nil, err
+		}
+	}
+
+	if s := first(git, "git.commit.id.full", "git.commit.id"); s != "" {
+		labels = append(labels, libcnb.Label{Key: LabelImageRevision, Value: s})
+	}
+
+	if s := first(git, "git.remote.origin.url"); s != "" {
+		labels = append(labels, libcnb.Label{Key: LabelImageSource, Value: redactURL(s)})
+	}
+
+	if s := first(buildInfo, "build.time"); s != "" {
+		labels = append(labels, libcnb.Label{Key: LabelImageCreated, Value: normalizeTime(s)})
+	} else if s := first(git, "git.build.time"); s != "" {
+		labels = append(labels, libcnb.Label{Key: LabelImageCreated, Value: normalizeTime(s)})
+	}
+
+	if s := first(buildInfo, "build.vendor"); s != "" {
+		labels = append(labels, libcnb.Label{Key: LabelImageVendor, Value: s})
+	}
+
+	if s := first(buildInfo, "build.description"); s != "" {
+		labels = append(labels, libcnb.Label{Key: LabelImageDescription, Value: s})
+	}
+
+	if s := first(buildInfo, "build.group"); s != "" {
+		labels = append(labels, libcnb.Label{Key: LabelBuildGroup, Value: s})
+	}
+
+	if s := first(buildInfo, "build.artifact"); s != "" {
+		labels = append(labels, libcnb.Label{Key: LabelBuildArtifact, Value: s})
+	}
+
+	if s := first(buildInfo, "build.time"); s != "" {
+		labels = append(labels, libcnb.Label{Key: LabelBuildTime, Value: normalizeTime(s)})
+	}
+
+	return labels, nil
+}
+
+// load reads a properties file from the application classes, falling back to the application root.
+func (p Provenance) load(name string) (*properties.Properties, error) {
+	for _, dir := range []string{p.Classes, ""} {
+		file := filepath.Join(p.ApplicationPath, dir, name)
+
+		b, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to read %s\n%w", file, err)
+		}
+
+		l := properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}
+		props, err := l.LoadBytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load properties from %s\n%w", file, err)
+		}
+
+		return props, nil
+	}
+
+	return nil, nil
+}
+
+func first(props *properties.Properties, keys ...string) string {
+	if props == nil {
+		return ""
+	}
+
+	for _, k := range keys {
+		if s, ok := props.Get(k); ok && s != "" {
+			return s
+		}
+	}
+
+	return ""
+}
+
+// redactURL removes any credentials from a remote URL so that they do not end up in an image label.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+
+	u.User = nil
+	return u.String()
+}
+
+func normalizeTime(s string) string {
+	for _, layout := range buildTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return s
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testProvenance(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path string
+		p    boot.Provenance
+	)
+
+	it.Before(func() {
+		var err error
+
+		path, err = os.MkdirTemp("", "provenance")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(path, "BOOT-INF", "classes", "META-INF"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "BOOT-INF", "classes", "git.properties"), []byte(`
+git.branch=main
+git.commit.id=0123456789abcdef0123456789abcdef01234567
+git.commit.id.abbrev=0123456
+git.remote.origin.url=https\://user\:secret@github.com/example/demo.git
+git.build.time=2024-01-02T03\:04\:05+0100
+`), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "BOOT-INF", "classes", "META-INF", "build-info.properties"), []byte(`
+build.artifact=demo
+build.group=com.example
+build.name=Demo
+build.time=2024-02-03T04\:05\:06.789Z
+build.version=1.0.0
+build.vendor=Example Corp
+build.description=A demo application
+`), 0644)).To(Succeed())
+
+		p = boot.Provenance{ApplicationPath: path, Classes: "BOOT-INF/classes"}
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+	})
+
+	it("contributes labels from git.properties and build-info.properties", func() {
+		Expect(p.Labels()).To(Equal([]libcnb.Label{
+			{Key: "org.opencontainers.image.revision", Value: "0123456789abcdef0123456789abcdef01234567"},
+			{Key: "org.opencontainers.image.source", Value: "https://github.com/example/demo.git"},
+			{Key: "org.opencontainers.image.created", Value: "2024-02-03T04:05:06Z"},
+			{Key: "org.opencontainers.image.vendor", Value: "Example Corp"},
+			{Key: "org.opencontainers.image.description", Value: "A demo application"},
+			{Key: "org.springframework.boot.build.group", Value: "com.example"},
+			{Key: "org.springframework.boot.build.artifact", Value: "demo"},
+			{Key: "org.springframework.boot.build.time", Value: "2024-02-03T04:05:06Z"},
+		}))
+	})
+
+	it("prefers the full commit id", func() {
+		Expect(os.WriteFile(filepath.Join(path, "BOOT-INF", "classes", "git.properties"), []byte(`
+git.commit.id=abbreviated
+git.commit.id.full=full
+`), 0644)).To(Succeed())
+
+		Expect(p.Labels()).To(ContainElement(libcnb.Label{Key: "org.opencontainers.image.revision", Value: "full"}))
+	})
+
+	it("does not contribute git labels when disabled", func() {
+		p.GitDisabled = true
+
+		Expect(p.Labels()).To(Equal([]libcnb.Label{
+			{Key: "org.opencontainers.image.created", Value: "2024-02-03T04:05:06Z"},
+			{Key: "org.opencontainers.image.vendor", Value: "Example Corp"},
+			{Key: "org.opencontainers.image.description", Value: "A demo application"},
+			{Key: "org.springframework.boot.build.group", Value: "com.example"},
+			{Key: "org.springframework.boot.build.artifact", Value: "demo"},
+			{Key: "org.springframework.boot.build.time", Value: "2024-02-03T04:05:06Z"},
+		}))
+	})
+
+	it("does not contribute build-info labels when disabled", func() {
+		p.BuildInfoDisabled = true
+
+		Expect(p.Labels()).To(Equal([]libcnb.Label{
+			{Key: "org.opencontainers.image.revision", Value: "0123456789abcdef0123456789abcdef01234567"},
+			{Key: "org.opencontainers.image.source", Value: "https://github.com/example/demo.git"},
+			{Key: "org.opencontainers.image.created", Value: "2024-01-02T02:04:05Z"},
+		}))
+	})
+
+	it("reads build-info.properties from the application root", func() {
+		Expect(os.RemoveAll(filepath.Join(path, "BOOT-INF"))).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(path, "META-INF"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, "META-INF", "build-info.properties"), []byte("build.group=com.example"), 0644)).To(Succeed())
+
+		Expect(p.Labels()).To(Equal([]libcnb.Label{
+			{Key: "org.springframework.boot.build.group", Value: "com.example"},
+		}))
+	})
+
+	it("contributes nothing without provenance files", func() {
+		Expect(os.RemoveAll(filepath.Join(path, "BOOT-INF"))).To(Succeed())
+
+		Expect(p.Labels()).To(BeEmpty())
+	})
+}
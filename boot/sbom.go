@@ -0,0 +1,241 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/paketo-buildpacks/libpak/sbom"
+)
+
+// mavenJARName matches jar file names following Maven naming conventions, such as spring-core-6.1.0.jar.
+var mavenJARName = regexp.MustCompile(`^(.+)-(\d.*)\.jar$`)
+
+// SBOMComponent is a dependency described by a software bill of materials.
+type SBOMComponent struct {
+	Group    string
+	Name     string
+	Version  string
+	PURL     string
+	SHA256   string
+	Licenses []string
+
+	// Path is the location of the dependency in the image.
+	Path string
+}
+
+// ApplicationSBOMComponents returns the components of the jars of index. Jars are identified by the Maven coordinates
+// they contain, or by their file name.
+func ApplicationSBOMComponents(index JarIndex) []SBOMComponent {
+	components := make([]SBOMComponent, 0, len(index.Jars))
+
+	for _, e := range index.Jars {
+		c := SBOMComponent{SHA256: e.SHA256, Licenses: NormalizeLicenses(e.Licenses), Path: e.Path}
+
+		if coordinates, ok := e.MavenCoordinates(); ok {
+			c.Group, c.Name, c.Version = coordinates.GroupID, coordinates.ArtifactID, coordinates.Version
+			c.PURL = fmt.Sprintf("pkg:maven/%s/%s@%s", coordinates.GroupID, coordinates.ArtifactID, coordinates.Version)
+		} else if m := mavenJARName.FindStringSubmatch(filepath.Base(e.Path)); m != nil {
+			c.Name, c.Version = m[1], m[2]
+		} else {
+			c.Name = filepath.Base(e.Path)
+		}
+
+		components = append(components, c)
+	}
+
+	return components
+}
+
+// DependencySBOMComponent returns the component of a buildpack dependency installed at path.
+func DependencySBOMComponent(dependency libpak.BuildpackDependency, path string) SBOMComponent {
+	c := SBOMComponent{
+		Name:    dependency.Name,
+		Version: dependency.Version,
+		PURL:    dependency.PURL,
+		SHA256:  dependency.SHA256,
+		Path:    path,
+	}
+	for _, l := range dependency.Licenses {
+		c.Licenses = append(c.Licenses, l.Type)
+	}
+	return c
+}
+
+// WriteSBOM writes the components in the CycloneDX and Syft formats, using path to create the path of each format.
+func WriteSBOM(path func(libcnb.SBOMFormat) string, source string, components []SBOMComponent) error {
+	if err := writeJSON(path(libcnb.CycloneDXJSON), cycloneDX(components)); err != nil {
+		return fmt.Errorf("unable to write CycloneDX SBOM\n%w", err)
+	}
+
+	s, err := syft(source, components)
+	if err != nil {
+		return fmt.Errorf("unable to create Syft SBOM\n%w", err)
+	}
+	if err := writeJSON(path(libcnb.SyftJSON), s); err != nil {
+		return fmt.Errorf("unable to write Syft SBOM\n%w", err)
+	}
+
+	return nil
+}
+
+type cycloneDXHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type cycloneDXLicense struct {
+	License struct {
+		ID   string `json:"id,omitempty"`
+		Name string `json:"name,omitempty"`
+	} `json:"license"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef   string             `json:"bom-ref"`
+	Type     string             `json:"type"`
+	Group    string             `json:"group,omitempty"`
+	Name     string             `json:"name"`
+	Version  string             `json:"version,omitempty"`
+	PURL     string             `json:"purl,omitempty"`
+	Hashes   []cycloneDXHash    `json:"hashes,omitempty"`
+	Licenses []cycloneDXLicense `json:"licenses,omitempty"`
+}
+
+type cycloneDXBOM struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Components  []cycloneDXComponent `json:"components"`
+}
+
+func cycloneDX(components []SBOMComponent) cycloneDXBOM {
+	bom := cycloneDXBOM{BOMFormat: "CycloneDX", SpecVersion: "1.4", Version: 1, Components: []cycloneDXComponent{}}
+	refs := make(map[string]bool)
+
+	for _, c := range components {
+		cc := cycloneDXComponent{
+			BOMRef:  c.PURL,
+			Type:    "library",
+			Group:   c.Group,
+			Name:    c.Name,
+			Version: c.Version,
+			PURL:    c.PURL,
+		}
+		if cc.BOMRef != "" && c.SHA256 != "" {
+			// copies of an artifact, such as a shaded or repackaged jar, share coordinates but not their digest
+			cc.BOMRef = fmt.Sprintf("%s?checksum=sha256:%s", cc.BOMRef, c.SHA256)
+		}
+		if cc.BOMRef == "" || refs[cc.BOMRef] {
+			cc.BOMRef = c.Path
+		}
+		refs[cc.BOMRef] = true
+		if c.SHA256 != "" {
+			cc.Hashes = []cycloneDXHash{{Algorithm: "SHA-256", Content: c.SHA256}}
+		}
+		for _, l := range c.Licenses {
+			var license cycloneDXLicense
+			if id, ok := SPDXLicense(l); ok {
+				license.License.ID = id
+			} else {
+				license.License.Name = l
+			}
+			cc.Licenses = append(cc.Licenses, license)
+		}
+
+		bom.Components = append(bom.Components, cc)
+	}
+
+	return bom
+}
+
+type syftDigest struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+}
+
+type syftJavaMetadata struct {
+	VirtualPath string       `json:"virtualPath"`
+	Digest      []syftDigest `json:"digest,omitempty"`
+}
+
+type syftArtifact struct {
+	sbom.SyftArtifact
+	MetadataType string
+	Metadata     syftJavaMetadata
+}
+
+type syftDocument struct {
+	Artifacts  []syftArtifact
+	Source     sbom.SyftSource
+	Descriptor sbom.SyftDescriptor
+	Schema     sbom.SyftSchema
+}
+
+func syft(source string, components []SBOMComponent) (syftDocument, error) {
+	d := sbom.NewSyftDependency(source, nil)
+	doc := syftDocument{Artifacts: []syftArtifact{}, Source: d.Source, Descriptor: d.Descriptor, Schema: d.Schema}
+
+	for _, c := range components {
+		a := syftArtifact{
+			SyftArtifact: sbom.SyftArtifact{
+				Name:      c.Name,
+				Version:   c.Version,
+				Type:      "java-archive",
+				FoundBy:   "spring-boot",
+				Locations: []sbom.SyftLocation{{Path: c.Path}},
+				Licenses:  append([]string{}, c.Licenses...),
+				Language:  "java",
+				CPEs:      []string{},
+				PURL:      c.PURL,
+			},
+			MetadataType: "JavaMetadata",
+			Metadata:     syftJavaMetadata{VirtualPath: c.Path},
+		}
+		if c.SHA256 != "" {
+			a.Metadata.Digest = []syftDigest{{Algorithm: "sha256", Value: c.SHA256}}
+		}
+
+		var err error
+		if a.ID, err = a.SyftArtifact.Hash(); err != nil {
+			return syftDocument{}, err
+		}
+
+		doc.Artifacts = append(doc.Artifacts, a)
+	}
+
+	return doc, nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("unable to marshal to JSON\n%w", err)
+	}
+
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		return fmt.Errorf("unable to write to path %s\n%w", path, err)
+	}
+
+	return nil
+}
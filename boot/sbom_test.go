@@ -0,0 +1,156 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testSBOM(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		index boot.JarIndex
+	)
+
+	it.Before(func() {
+		index = boot.JarIndex{Jars: []boot.JarIndexEntry{
+			{
+				Path:   "BOOT-INF/lib/demo-1.0.0.jar",
+				SHA256: "test-sha256",
+				Coordinates: []boot.MavenCoordinates{
+					{GroupID: "org.shaded", ArtifactID: "shaded", Version: "2.0.0"},
+					{GroupID: "com.example", ArtifactID: "demo", Version: "1.0.0"},
+				},
+				Licenses: []string{"The Apache Software License, Version 2.0", "Apache-2.0", "Custom License"},
+			},
+			{Path: "BOOT-INF/lib/other-2.0.0.jar", SHA256: "other-sha256"},
+			{Path: "BOOT-INF/lib/unversioned.jar"},
+		}}
+	})
+
+	it("creates components from the jar index", func() {
+		Expect(boot.ApplicationSBOMComponents(index)).To(Equal([]boot.SBOMComponent{
+			{
+				Group:    "com.example",
+				Name:     "demo",
+				Version:  "1.0.0",
+				PURL:     "pkg:maven/com.example/demo@1.0.0",
+				SHA256:   "test-sha256",
+				Licenses: []string{"Apache-2.0", "Custom License"},
+				Path:     "BOOT-INF/lib/demo-1.0.0.jar",
+			},
+			{Name: "other", Version: "2.0.0", SHA256: "other-sha256", Path: "BOOT-INF/lib/other-2.0.0.jar"},
+			{Name: "unversioned.jar", Path: "BOOT-INF/lib/unversioned.jar"},
+		}))
+	})
+
+	it("creates a component from a buildpack dependency", func() {
+		dep := libpak.BuildpackDependency{
+			Name:     "Spring Cloud Bindings",
+			Version:  "2.0.4",
+			SHA256:   "test-sha256",
+			PURL:     "pkg:generic/springframework/spring-cloud-bindings@2.0.4",
+			Licenses: []libpak.BuildpackDependencyLicense{{Type: "Apache-2.0"}},
+		}
+
+		Expect(boot.DependencySBOMComponent(dep, "/layers/spring-cloud-bindings.jar")).To(Equal(boot.SBOMComponent{
+			Name:     "Spring Cloud Bindings",
+			Version:  "2.0.4",
+			PURL:     "pkg:generic/springframework/spring-cloud-bindings@2.0.4",
+			SHA256:   "test-sha256",
+			Licenses: []string{"Apache-2.0"},
+			Path:     "/layers/spring-cloud-bindings.jar",
+		}))
+	})
+
+	it("writes CycloneDX and Syft SBOMs", func() {
+		layers := libcnb.Layers{Path: t.TempDir()}
+
+		Expect(boot.WriteSBOM(layers.LaunchSBOMPath, "/workspace", boot.ApplicationSBOMComponents(index))).To(Succeed())
+
+		var cdx map[string]interface{}
+		b, err := os.ReadFile(filepath.Join(layers.Path, "launch.sbom.cdx.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(b, &cdx)).To(Succeed())
+		Expect(cdx["bomFormat"]).To(Equal("CycloneDX"))
+		Expect(cdx["components"]).To(HaveLen(3))
+		Expect(cdx["components"].([]interface{})[0]).To(Equal(map[string]interface{}{
+			"bom-ref": "pkg:maven/com.example/demo@1.0.0?checksum=sha256:test-sha256",
+			"type":    "library",
+			"group":   "com.example",
+			"name":    "demo",
+			"version": "1.0.0",
+			"purl":    "pkg:maven/com.example/demo@1.0.0",
+			"hashes":  []interface{}{map[string]interface{}{"alg": "SHA-256", "content": "test-sha256"}},
+			"licenses": []interface{}{
+				map[string]interface{}{"license": map[string]interface{}{"id": "Apache-2.0"}},
+				map[string]interface{}{"license": map[string]interface{}{"name": "Custom License"}},
+			},
+		}))
+
+		var syft map[string]interface{}
+		b, err = os.ReadFile(filepath.Join(layers.Path, "launch.sbom.syft.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(b, &syft)).To(Succeed())
+		Expect(syft["Source"]).To(Equal(map[string]interface{}{"Type": "directory", "Target": "/workspace"}))
+
+		artifact := syft["Artifacts"].([]interface{})[0].(map[string]interface{})
+		Expect(artifact["PURL"]).To(Equal("pkg:maven/com.example/demo@1.0.0"))
+		Expect(artifact["Licenses"]).To(Equal([]interface{}{"Apache-2.0", "Custom License"}))
+		Expect(artifact["ID"]).NotTo(BeEmpty())
+		Expect(artifact["Metadata"]).To(Equal(map[string]interface{}{
+			"virtualPath": "BOOT-INF/lib/demo-1.0.0.jar",
+			"digest":      []interface{}{map[string]interface{}{"algorithm": "sha256", "value": "test-sha256"}},
+		}))
+	})
+
+	it("gives copies of an artifact distinct CycloneDX references", func() {
+		layers := libcnb.Layers{Path: t.TempDir()}
+		components := []boot.SBOMComponent{
+			{Group: "com.example", Name: "demo", Version: "1.0.0", PURL: "pkg:maven/com.example/demo@1.0.0", SHA256: "first-sha256", Path: "BOOT-INF/lib/demo-1.0.0.jar"},
+			{Group: "com.example", Name: "demo", Version: "1.0.0", PURL: "pkg:maven/com.example/demo@1.0.0", SHA256: "second-sha256", Path: "BOOT-INF/lib/demo-shaded.jar"},
+			{Group: "com.example", Name: "demo", Version: "1.0.0", PURL: "pkg:maven/com.example/demo@1.0.0", SHA256: "second-sha256", Path: "BOOT-INF/lib/demo-copy.jar"},
+		}
+
+		Expect(boot.WriteSBOM(layers.LaunchSBOMPath, "/workspace", components)).To(Succeed())
+
+		var cdx struct {
+			Components []struct {
+				BOMRef string `json:"bom-ref"`
+			} `json:"components"`
+		}
+		b, err := os.ReadFile(filepath.Join(layers.Path, "launch.sbom.cdx.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(b, &cdx)).To(Succeed())
+
+		Expect(cdx.Components).To(HaveLen(3))
+		Expect(cdx.Components[0].BOMRef).To(Equal("pkg:maven/com.example/demo@1.0.0?checksum=sha256:first-sha256"))
+		Expect(cdx.Components[1].BOMRef).To(Equal("pkg:maven/com.example/demo@1.0.0?checksum=sha256:second-sha256"))
+		Expect(cdx.Components[2].BOMRef).To(Equal("BOOT-INF/lib/demo-copy.jar"))
+	})
+}
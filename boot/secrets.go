@@ -0,0 +1,211 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/paketo-buildpacks/libpak/bard"
+)
+
+// secretKeys are the words identifying properties whose value is a secret, compared with whole words of the last
+// segment of property names, such as token in access-token or apikey in apiKey, but not tokens in max-tokens.
+var secretKeys = []string{"password", "passwd", "secret", "token", "apikey", "privatekey", "credentials"}
+
+// secretKeyExceptions are the suffixes of property names that only describe a secret, such as token-uri.
+var secretKeyExceptions = []string{
+	"uri", "url", "endpoint", "path", "file", "location", "header", "name", "type", "enabled", "length", "validity",
+	"timeout", "expiration", "attribute", "parameter", "algorithm", "prefix",
+}
+
+// SecretPattern identifies secrets by the form of their value, whatever the property.
+type SecretPattern struct {
+	Kind     string
+	Pattern  *regexp.Regexp
+	Severity Severity
+}
+
+// SecretPatterns are the forms of private keys and cloud credentials found in configuration files.
+var SecretPatterns = []SecretPattern{
+	{Kind: "private key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`), Severity: SeverityCritical},
+	{Kind: "AWS access key", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), Severity: SeverityCritical},
+	{Kind: "Azure storage account key", Pattern: regexp.MustCompile(`AccountKey=[A-Za-z0-9+/]{40,}={0,2}`), Severity: SeverityCritical},
+	{Kind: "GitHub token", Pattern: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`), Severity: SeverityCritical},
+	{Kind: "Google API key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`), Severity: SeverityHigh},
+	{Kind: "Slack token", Pattern: regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z-]{10,}\b`), Severity: SeverityHigh},
+	{Kind: "credentials in URL", Pattern: regexp.MustCompile(`[a-z][a-z0-9+.-]*://[^/\s:@]+:[^/\s@$]+@`), Severity: SeverityHigh},
+}
+
+// SecretFinding is a property of a configuration file whose value looks like a secret. It never holds the value.
+type SecretFinding struct {
+	// File is the path of the configuration file, relative to the application root.
+	File string
+
+	// Key is the name of the property.
+	Key string
+
+	// Kind describes why the value looks like a secret.
+	Kind string
+
+	Severity Severity
+}
+
+func (s SecretFinding) String() string {
+	return fmt.Sprintf("%s: %s (%s, %s)", s.File, s.Key, s.Kind, s.Severity)
+}
+
+// ScanConfigurationSecrets returns the properties of the configuration files in classes, including profile-specific
+// variants, that hold plaintext secrets: a literal value of a password, secret or token property, a private key or
+// cloud credentials. Values referencing other properties or environment variables with ${...} are not secrets.
+func ScanConfigurationSecrets(appPath string, classes string) ([]SecretFinding, error) {
+	documents, err := LoadConfigurationDocuments(filepath.Join(appPath, classes))
+	if err != nil {
+		return nil, err
+	}
+
+	type location struct{ file, key string }
+	findings := make(map[location]SecretFinding)
+
+	for _, d := range documents {
+		file := filepath.ToSlash(filepath.Join(classes, d.Origin))
+
+		for k, v := range d.Properties {
+			f, ok := secretFinding(k, v)
+			if !ok {
+				continue
+			}
+			f.File = file
+
+			l := location{file, k}
+			if existing, ok := findings[l]; !ok || f.Severity > existing.Severity {
+				findings[l] = f
+			}
+		}
+	}
+
+	var result []SecretFinding
+	for _, f := range findings {
+		result = append(result, f)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		return result[i].Key < result[j].Key
+	})
+
+	return result, nil
+}
+
+func secretFinding(key string, value string) (SecretFinding, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return SecretFinding{}, false
+	}
+
+	for _, p := range SecretPatterns {
+		if p.Pattern.MatchString(value) {
+			return SecretFinding{Key: key, Kind: p.Kind, Severity: p.Severity}, true
+		}
+	}
+
+	if strings.Contains(value, "${") || !isSecretKey(key) {
+		return SecretFinding{}, false
+	}
+
+	return SecretFinding{Key: key, Kind: "literal value", Severity: SeverityHigh}, true
+}
+
+func isSecretKey(key string) bool {
+	segment := key[strings.LastIndex(key, ".")+1:]
+	segment = configurationIndex.ReplaceAllString(segment, "")
+	canonical := CanonicalConfigurationName(segment)
+
+	for _, e := range secretKeyExceptions {
+		if strings.HasSuffix(canonical, e) {
+			return false
+		}
+	}
+
+	words := configurationWords(segment)
+	for i := range words {
+		joined := ""
+		for _, w := range words[i:] {
+			joined += w
+			for _, s := range secretKeys {
+				if joined == s {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// configurationWords splits a property name in lower case words at dashes, underscores and changes of case, such as
+// api and key for api-key, apiKey, API_KEY and APIKey.
+func configurationWords(name string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		runes := []rune(part)
+
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			if unicode.IsUpper(runes[i]) && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+				start = i
+			}
+		}
+		words = append(words, strings.ToLower(string(runes[start:])))
+	}
+	return words
+}
+
+// ReportSecrets logs what looks like secrets in the packaged configuration. It returns an error if a finding is of
+// threshold severity or higher, unless threshold is SeverityUnknown.
+func ReportSecrets(logger bard.Logger, appPath string, classes string, threshold Severity) error {
+	findings, err := ScanConfigurationSecrets(appPath, classes)
+	if err != nil {
+		return fmt.Errorf("unable to scan configuration for secrets\n%w", err)
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+
+	var all, failing []string
+	for _, f := range findings {
+		all = append(all, f.String())
+		if threshold != SeverityUnknown && f.Severity >= threshold {
+			failing = append(failing, f.String())
+		}
+	}
+
+	if len(failing) > 0 {
+		return fmt.Errorf("the packaged configuration contains secrets of severity %s or higher, "+
+			"provide them through the environment or a binding instead:\n  %s", threshold, strings.Join(failing, "\n  "))
+	}
+
+	logger.Header(Warningf("WARNING: the packaged configuration contains what looks like secrets, "+
+		"provide them through the environment or a binding instead:\n  %s", strings.Join(all, "\n  ")))
+	return nil
+}
@@ -64,6 +64,10 @@ func (s SpringCloudBindings) Contribute(layer libcnb.Layer) (libcnb.Layer, error
 		return libcnb.Layer{}, fmt.Errorf("unable to contribute spring-cloud-bindings layer\n%w", err)
 	}
 
+	if err := WriteSBOM(layer.SBOMPath, layer.Path, []SBOMComponent{DependencySBOMComponent(s.Dependency, file)}); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to write spring-cloud-bindings SBOM\n%w", err)
+	}
+
 	if err := os.MkdirAll(s.SpringBootLib, 0755); err != nil {
 		return libcnb.Layer{}, fmt.Errorf("unable to create directory %s\n%w", s.SpringBootLib, err)
 	}
@@ -70,5 +70,10 @@ func testSpringCloudBindings(t *testing.T, context spec.G, it spec.S) {
 		Expect(filepath.Join(layer.Path, "stub-spring-cloud-bindings.jar")).To(BeARegularFile())
 		Expect(os.Readlink(filepath.Join(ctx.Application.Path, "test-lib", "stub-spring-cloud-bindings.jar"))).
 			To(Equal(filepath.Join(layer.Path, "stub-spring-cloud-bindings.jar")))
+
+		b, err := os.ReadFile(layer.SBOMPath(libcnb.CycloneDXJSON))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring(`{"alg":"SHA-256","content":"723126712c0b22a7fe409664adf1fbb78cf3040e313a82c06696f5058e190534"}`))
+		Expect(layer.SBOMPath(libcnb.SyftJSON)).To(BeARegularFile())
 	})
 }
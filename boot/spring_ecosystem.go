@@ -0,0 +1,148 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/buildpacks/libcnb"
+)
+
+const (
+	LabelSpringFrameworkVersion = "org.springframework.version"
+	LabelSpringCloudVersion     = "org.springframework.cloud.version"
+	LabelSpringSecurityVersion  = "org.springframework.security.version"
+	LabelServer                 = "org.springframework.boot.server"
+	LabelServerVersion          = "org.springframework.boot.server.version"
+	LabelApplicationType        = "org.springframework.boot.application-type"
+	LabelJavaTarget             = "org.springframework.boot.java.target"
+)
+
+// EmbeddedServer is a web server embedded in a Spring Boot application, along with the class and artifact that
+// identify it.
+type EmbeddedServer struct {
+	Name     string
+	Group    string
+	Artifact string
+	Class    string
+
+	// DefaultThreads is the default size of the request thread pool of a servlet container.
+	DefaultThreads int
+}
+
+var (
+	Tomcat   = EmbeddedServer{Name: "tomcat", Group: "org.apache.tomcat.embed", Artifact: "tomcat-embed-core", Class: "org.apache.catalina.startup.Tomcat", DefaultThreads: 200}
+	Jetty    = EmbeddedServer{Name: "jetty", Group: "org.eclipse.jetty", Artifact: "jetty-server", Class: "org.eclipse.jetty.server.Server", DefaultThreads: 200}
+	Undertow = EmbeddedServer{Name: "undertow", Group: "io.undertow", Artifact: "undertow-core", Class: "io.undertow.Undertow", DefaultThreads: 64}
+	Netty    = EmbeddedServer{Name: "netty", Group: "io.projectreactor.netty", Artifact: "reactor-netty-http", Class: "reactor.netty.http.server.HttpServer"}
+)
+
+// ServletServers are listed in the order Spring Boot prefers them when several are on the classpath of a servlet web
+// application.
+var ServletServers = []EmbeddedServer{Tomcat, Jetty, Undertow}
+
+// ReactiveServers are listed in the order Spring Boot prefers them when several are on the classpath of a reactive
+// web application.
+var ReactiveServers = []EmbeddedServer{Netty, Tomcat, Jetty, Undertow}
+
+// SpringProject is a Spring project whose support generations are checked, identified by its main artifact.
+type SpringProject struct {
+	Slug     string
+	Group    string
+	Artifact string
+}
+
+// SpringProjects are the projects, besides Spring Boot itself, whose generations are validated.
+var SpringProjects = []SpringProject{
+	{Slug: "spring-framework", Group: "org.springframework", Artifact: "spring-core"},
+	{Slug: "spring-security", Group: "org.springframework.security", Artifact: "spring-security-core"},
+	{Slug: "spring-data-jpa", Group: "org.springframework.data", Artifact: "spring-data-jpa"},
+	{Slug: "spring-integration", Group: "org.springframework.integration", Artifact: "spring-integration-core"},
+	{Slug: "spring-batch", Group: "org.springframework.batch", Artifact: "spring-batch-core"},
+	{Slug: "spring-kafka", Group: "org.springframework.kafka", Artifact: "spring-kafka"},
+	{Slug: "spring-amqp", Group: "org.springframework.amqp", Artifact: "spring-amqp"},
+}
+
+// springCloudReleaseTrains maps the major.minor version of spring-cloud-commons to the Spring Cloud release train it
+// ships with.
+var springCloudReleaseTrains = map[string]string{
+	"2.0": "Finchley",
+	"2.1": "Greenwich",
+	"2.2": "Hoxton",
+	"3.0": "2020.0",
+	"3.1": "2021.0",
+	"4.0": "2022.0",
+	"4.1": "2023.0",
+	"4.2": "2024.0",
+	"4.3": "2025.0",
+	"5.0": "2025.1",
+}
+
+// SpringEcosystemLabels returns image labels describing the Spring projects and Java release the application is built
+// with.
+func SpringEcosystemLabels(dependencies []Dependency, javaTarget int) []libcnb.Label {
+	var labels []libcnb.Label
+
+	if v, ok := findDependencyVersion(dependencies, "org.springframework", "spring-core"); ok {
+		labels = append(labels, libcnb.Label{Key: LabelSpringFrameworkVersion, Value: v})
+	}
+
+	if v, ok := findDependencyVersion(dependencies, "org.springframework.cloud", "spring-cloud-commons"); ok {
+		if train, ok := SpringCloudReleaseTrain(v); ok {
+			labels = append(labels, libcnb.Label{Key: LabelSpringCloudVersion, Value: train})
+		}
+	}
+
+	if v, ok := findDependencyVersion(dependencies, "org.springframework.security", "spring-security-core"); ok {
+		labels = append(labels, libcnb.Label{Key: LabelSpringSecurityVersion, Value: v})
+	}
+
+	if javaTarget > 0 {
+		labels = append(labels, libcnb.Label{Key: LabelJavaTarget, Value: strconv.Itoa(javaTarget)})
+	}
+
+	return labels
+}
+
+// EmbeddedServerLabels returns image labels describing the embedded server and, when its artifact is found, its
+// version.
+func EmbeddedServerLabels(server EmbeddedServer, dependencies []Dependency) []libcnb.Label {
+	labels := []libcnb.Label{{Key: LabelServer, Value: server.Name}}
+
+	if v, ok := findDependencyVersion(dependencies, server.Group, server.Artifact); ok {
+		labels = append(labels, libcnb.Label{Key: LabelServerVersion, Value: v})
+	}
+
+	return labels
+}
+
+// SpringCloudReleaseTrain returns the Spring Cloud release train matching a spring-cloud-commons version.
+func SpringCloudReleaseTrain(commonsVersion string) (string, bool) {
+	v, err := bootVersion(commonsVersion)
+	if err != nil {
+		return "", false
+	}
+
+	train, ok := springCloudReleaseTrains[fmt.Sprintf("%d.%d", v.Major(), v.Minor())]
+	return train, ok
+}
+
+func findDependencyVersion(dependencies []Dependency, group string, name string) (string, bool) {
+	d, ok := FindDependency(dependencies, group, name)
+	return d.Version, ok
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testSpringEcosystem(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	it("contributes labels for Spring projects", func() {
+		Expect(boot.SpringEcosystemLabels([]boot.Dependency{
+			{Name: "jetty-server", Version: "12.0.5"},
+			{Name: "spring-cloud-commons", Version: "4.1.0"},
+			{Name: "spring-core", Version: "6.1.2"},
+			{Name: "spring-security-core", Version: "6.2.1"},
+			{Name: "tomcat-embed-core", Version: "10.1.17"},
+		}, 17)).To(Equal([]libcnb.Label{
+			{Key: "org.springframework.version", Value: "6.1.2"},
+			{Key: "org.springframework.cloud.version", Value: "2023.0"},
+			{Key: "org.springframework.security.version", Value: "6.2.1"},
+			{Key: "org.springframework.boot.java.target", Value: "17"},
+		}))
+	})
+
+	it("contributes embedded server labels", func() {
+		Expect(boot.EmbeddedServerLabels(boot.Netty, []boot.Dependency{
+			{Name: "reactor-netty-http", Version: "1.1.14"},
+		})).To(Equal([]libcnb.Label{
+			{Key: "org.springframework.boot.server", Value: "netty"},
+			{Key: "org.springframework.boot.server.version", Value: "1.1.14"},
+		}))
+	})
+
+	it("contributes embedded server label without version", func() {
+		Expect(boot.EmbeddedServerLabels(boot.Jetty, nil)).To(Equal([]libcnb.Label{
+			{Key: "org.springframework.boot.server", Value: "jetty"},
+		}))
+	})
+
+	it("ignores artifacts of other groups", func() {
+		Expect(boot.SpringEcosystemLabels([]boot.Dependency{
+			{Group: "com.example", Name: "spring-core", Version: "1.0.0"},
+		}, 0)).To(BeEmpty())
+	})
+
+	it("contributes nothing without known dependencies", func() {
+		Expect(boot.SpringEcosystemLabels([]boot.Dependency{{Name: "test", Version: "1"}}, 0)).To(BeEmpty())
+	})
+
+	it("maps spring-cloud-commons versions to release trains", func() {
+		train, ok := boot.SpringCloudReleaseTrain("2.2.9.RELEASE")
+		Expect(ok).To(BeTrue())
+		Expect(train).To(Equal("Hoxton"))
+
+		train, ok = boot.SpringCloudReleaseTrain("4.0.4")
+		Expect(ok).To(BeTrue())
+		Expect(train).To(Equal("2022.0"))
+
+		_, ok = boot.SpringCloudReleaseTrain("9.9.9")
+		Expect(ok).To(BeFalse())
+	})
+}
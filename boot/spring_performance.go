@@ -46,6 +46,9 @@ type SpringPerformance struct {
 	ClasspathString            string
 	ReZip                      bool
 	TrainingRunJavaToolOptions string
+
+	// AOTCacheJava is the Java release from which the training run creates an AOT cache rather than a CDS archive.
+	AOTCacheJava int
 }
 
 func NewSpringPerformance(cache libpak.DependencyCache, appPath string, manifest *properties.Properties, aotEnabled bool, performanceType SpringPerformanceType, classpathString string, reZip bool, trainingRunJavaToolOptions string) SpringPerformance {
@@ -137,12 +140,18 @@ func (s SpringPerformance) Contribute(layer libcnb.Layer) (libcnb.Layer, error)
 		}
 
 		trainingRunArgs = append(trainingRunArgs, "-Dspring.context.exit=onRefresh")
-		if jreVersion >= 25 {
+		aotCacheJava := s.AOTCacheJava
+		if aotCacheJava == 0 {
+			aotCacheJava = DefaultAOTCacheJava
+		}
+		if jreVersion >= aotCacheJava {
 			// we can use https://openjdk.org/jeps/514
 			trainingRunArgs = append(trainingRunArgs, "-XX:AOTCacheOutput=application.aot")
 		} else {
 			trainingRunArgs = append(trainingRunArgs, "-XX:ArchiveClassesAtExit=application.jsa")
 		}
+		classLoadLog := filepath.Join(os.TempDir(), fmt.Sprintf("class-load-%d.log", time.Now().UnixMilli()))
+		trainingRunArgs = append(trainingRunArgs, fmt.Sprintf("-Xlog:class+load=info:file=%s", classLoadLog))
 		trainingRunArgs = append(trainingRunArgs, "-cp", s.ClasspathString, startClassValue)
 
 		var trainingRunEnvVariables []string
@@ -164,6 +173,13 @@ func (s SpringPerformance) Contribute(layer libcnb.Layer) (libcnb.Layer, error)
 			return libcnb.Layer{}, fmt.Errorf("error running build\n%w", err)
 		}
 
+		// the classes loaded by the training run replace the memory calculator's estimate
+		if n, err := CountLoadedClasses(classLoadLog); err == nil && n > 0 {
+			s.Logger.Bodyf("Training run loaded %d classes", n)
+			layer.LaunchEnvironment.Default("BPL_JVM_LOADED_CLASS_COUNT", n)
+			os.Remove(classLoadLog)
+		}
+
 		return layer, nil
 	})
 
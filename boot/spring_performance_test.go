@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/buildpacks/libcnb"
@@ -117,6 +118,85 @@ Spring-Boot-Lib: BOOT-INF/lib
 
 	})
 
+	it("contributes loaded class count from the training run", func() {
+		Expect(os.Setenv("JRE_HOME", "/that/does/not/exist")).To(Succeed())
+
+		performanceType = boot.CdsAotCache
+		dc := libpak.DependencyCache{CachePath: "testdata"}
+		executor.On("Execute", mock.Anything).
+			Run(func(args mock.Arguments) {
+				execution := args.Get(0).(effect.Execution)
+				if slices.Contains(execution.Args, "-version") && execution.Stderr != nil {
+					_, err := io.WriteString(execution.Stderr, javaVersion21Output)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				for _, arg := range execution.Args {
+					if file, ok := strings.CutPrefix(arg, "-Xlog:class+load=info:file="); ok {
+						Expect(os.WriteFile(file, []byte(`[0.010s][info][class,load] java.lang.Object source: shared objects file
+[0.011s][info][class,load] java.lang.String source: shared objects file
+[0.200s][info][class,load] com.example.Application source: file:/workspace/
+`), 0644)).To(Succeed())
+					}
+				}
+			}).Return(nil)
+
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Spring-Boot-Version: 3.3.1
+Spring-Boot-Classes: BOOT-INF/classes
+Spring-Boot-Lib: BOOT-INF/lib
+`), 0644)).To(Succeed())
+		props, err := libjvm.NewManifest(ctx.Application.Path)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := boot.NewSpringPerformance(dc, ctx.Application.Path, props, aotEnabled, performanceType, "", true, "")
+		s.Executor = executor
+
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = s.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LaunchEnvironment["BPL_JVM_LOADED_CLASS_COUNT.default"]).To(Equal("3"))
+	})
+
+	it("creates an AOT cache from the Java version of the Spring Boot generation", func() {
+		Expect(os.Setenv("JRE_HOME", "/that/does/not/exist")).To(Succeed())
+
+		performanceType = boot.CdsAotCache
+		dc := libpak.DependencyCache{CachePath: "testdata"}
+		executor.On("Execute", mock.Anything).
+			Run(func(args mock.Arguments) {
+				execution := args.Get(0).(effect.Execution)
+				if slices.Contains(execution.Args, "-version") && execution.Stderr != nil {
+					_, err := io.WriteString(execution.Stderr, javaVersion21Output)
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}).Return(nil)
+
+		Expect(os.WriteFile(filepath.Join(ctx.Application.Path, "META-INF", "MANIFEST.MF"), []byte(`
+Spring-Boot-Version: 3.3.1
+Spring-Boot-Classes: BOOT-INF/classes
+Spring-Boot-Lib: BOOT-INF/lib
+`), 0644)).To(Succeed())
+		props, err := libjvm.NewManifest(ctx.Application.Path)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := boot.NewSpringPerformance(dc, ctx.Application.Path, props, aotEnabled, performanceType, "", true, "")
+		s.Executor = executor
+		s.AOTCacheJava = 21
+
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = s.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		e, ok := executor.Calls[2].Arguments[0].(effect.Execution)
+		Expect(ok).To(BeTrue())
+		Expect(e.Args).To(ContainElement("-XX:AOTCacheOutput=application.aot"))
+	})
+
 	it("contributes Spring Performance for Boot 3.3+, AOT only enabled", func() {
 		aotEnabled = true
 		performanceType = boot.Without
@@ -0,0 +1,243 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/bindings"
+	"github.com/paketo-buildpacks/libpak/sherpa"
+)
+
+const (
+	ThinJarWrapperClass        = "org.springframework.boot.loader.wrapper.ThinJarWrapper"
+	BindingTypeMavenRepository = "maven-repository"
+	ThinClasses                = "."
+	ThinLib                    = "lib"
+)
+
+// ThinApplication is an application packaged with the Spring Boot Thin Launcher. Its dependencies are not part of the
+// archive but are listed in META-INF/thin.properties or META-INF/maven/**/pom.xml and would normally be downloaded
+// when the application starts.
+type ThinApplication struct {
+	Logger     bard.Logger
+	Path       string
+	Manifest   *properties.Properties
+	Repository *MavenRepository
+}
+
+// IsThinApplication returns whether the application at appPath is packaged with the Spring Boot Thin Launcher.
+func IsThinApplication(appPath string, manifest *properties.Properties) (bool, error) {
+	if mainClass, ok := manifest.Get("Main-Class"); ok && mainClass == ThinJarWrapperClass {
+		return true, nil
+	}
+
+	return sherpa.FileExists(filepath.Join(appPath, "META-INF", "thin.properties"))
+}
+
+// ThinRepository returns the root of the Maven repository used to resolve the dependencies of a thin application. A
+// binding of type maven-repository takes precedence over BP_SPRING_BOOT_THIN_REPOSITORY. The binding either contains
+// a path entry pointing to the repository, or is the repository itself.
+func ThinRepository(binds libcnb.Bindings, appPath string) (string, error) {
+	if b, ok, err := bindings.ResolveOne(binds, bindings.OfType(BindingTypeMavenRepository)); err != nil {
+		return "", fmt.Errorf("unable to resolve binding %s\n%w", BindingTypeMavenRepository, err)
+	} else if ok {
+		if p, ok := b.Secret["path"]; ok {
+			return strings.TrimSpace(p), nil
+		}
+		return b.Path, nil
+	}
+
+	if p := sherpa.GetEnvWithDefault("BP_SPRING_BOOT_THIN_REPOSITORY", ""); p != "" {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(appPath, p)
+		}
+		return p, nil
+	}
+
+	return "", fmt.Errorf("unable to find a Maven repository for the thin application; "+
+		"provide a binding of type %s or set BP_SPRING_BOOT_THIN_REPOSITORY", BindingTypeMavenRepository)
+}
+
+// NewThinApplication creates a ThinApplication resolving its dependencies from the Maven repository at repository.
+func NewThinApplication(appPath string, manifest *properties.Properties, repository string) ThinApplication {
+	return ThinApplication{
+		Path:       appPath,
+		Manifest:   manifest,
+		Repository: NewMavenRepository(repository),
+	}
+}
+
+// Resolve resolves the dependencies of the thin application, copies them into the lib directory of the application,
+// and returns the application described as an ExplodedApplication that starts without network access.
+func (t ThinApplication) Resolve() (ExplodedApplication, error) {
+	d, err := t.dependencies()
+	if err != nil {
+		return ExplodedApplication{}, err
+	}
+
+	resolved := d.Roots
+	if !d.Computed {
+		if resolved, err = t.Repository.Resolve(d.Roots, d.Managed); err != nil {
+			return ExplodedApplication{}, fmt.Errorf("unable to resolve dependencies\n%w", err)
+		}
+	}
+
+	var artifacts []MavenCoordinates
+	for _, a := range resolved {
+		if !d.Exclusions[a.GroupID+":"+a.ArtifactID] {
+			artifacts = append(artifacts, a)
+		}
+	}
+
+	lib := filepath.Join(t.Path, ThinLib)
+	if err := os.MkdirAll(lib, 0755); err != nil {
+		return ExplodedApplication{}, fmt.Errorf("unable to create directory %s\n%w", lib, err)
+	}
+
+	var missing []string
+	version := ""
+	for _, a := range artifacts {
+		source := t.Repository.ArtifactPath(a)
+		if ok, err := sherpa.FileExists(source); err != nil {
+			return ExplodedApplication{}, fmt.Errorf("unable to check file %s\n%w", source, err)
+		} else if !ok {
+			missing = append(missing, a.String())
+			continue
+		}
+
+		if err := copyFile(source, filepath.Join(lib, a.FileName())); err != nil {
+			return ExplodedApplication{}, err
+		}
+		t.Logger.Bodyf("Resolved %s", a)
+
+		if a.GroupID == "org.springframework.boot" && a.ArtifactID == "spring-boot" {
+			version = a.Version
+		}
+	}
+
+	if len(missing) > 0 {
+		return ExplodedApplication{}, fmt.Errorf("unable to find %d dependencies in Maven repository %s: %s",
+			len(missing), t.Repository.Root, strings.Join(missing, ", "))
+	}
+
+	if v, ok := t.Manifest.Get("Spring-Boot-Version"); ok {
+		version = v
+	}
+	if version == "" {
+		return ExplodedApplication{}, fmt.Errorf("unable to determine the Spring Boot version of the thin application")
+	}
+
+	mainClass, _ := t.Manifest.Get("Start-Class")
+	if mainClass == "" {
+		return ExplodedApplication{}, fmt.Errorf("manifest does not contain Start-Class")
+	}
+
+	return ExplodedApplication{
+		Path:      t.Path,
+		Classes:   ThinClasses,
+		Lib:       ThinLib,
+		Version:   version,
+		MainClass: mainClass,
+	}, nil
+}
+
+type thinDependencies struct {
+	Roots      []MavenCoordinates
+	Managed    []MavenCoordinates
+	Exclusions map[string]bool
+	Computed   bool
+}
+
+// dependencies returns the dependencies, dependency management and exclusions declared by the thin application. When
+// thin.properties was computed by the thin launcher build plugins, the dependencies are already the complete set.
+func (t ThinApplication) dependencies() (thinDependencies, error) {
+	d := thinDependencies{Exclusions: map[string]bool{}}
+
+	poms, err := filepath.Glob(filepath.Join(t.Path, "META-INF", "maven", "*", "*", "pom.xml"))
+	if err != nil {
+		return thinDependencies{}, fmt.Errorf("unable to find pom.xml\n%w", err)
+	}
+	sort.Strings(poms)
+	if len(poms) > 0 {
+		if d.Roots, d.Managed, err = t.Repository.Effective(poms[0]); err != nil {
+			return thinDependencies{}, fmt.Errorf("unable to read %s\n%w", poms[0], err)
+		}
+	}
+
+	file := filepath.Join(t.Path, "META-INF", "thin.properties")
+	if ok, err := sherpa.FileExists(file); err != nil {
+		return thinDependencies{}, fmt.Errorf("unable to check file %s\n%w", file, err)
+	} else if !ok {
+		return d, nil
+	}
+
+	p, err := properties.LoadFile(file, properties.UTF8)
+	if err != nil {
+		return thinDependencies{}, fmt.Errorf("unable to load properties from %s\n%w", file, err)
+	}
+
+	if d.Computed, _ = strconv.ParseBool(p.GetString("computed", "false")); d.Computed {
+		d.Roots, d.Managed = nil, nil
+	}
+
+	for _, prefix := range []string{"exclusions.", "boms.", "dependencies."} {
+		keys := p.FilterPrefix(prefix).Keys()
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			c, err := ParseMavenCoordinates(p.MustGet(k))
+			if err != nil {
+				return thinDependencies{}, fmt.Errorf("unable to parse %s in %s\n%w", k, file, err)
+			}
+
+			switch prefix {
+			case "exclusions.":
+				d.Exclusions[c.GroupID+":"+c.ArtifactID] = true
+			case "boms.":
+				c.Type = "pom"
+				d.Managed = append(d.Managed, c)
+			case "dependencies.":
+				d.Roots = append(d.Roots, c)
+			}
+		}
+	}
+
+	return d, nil
+}
+
+func copyFile(source string, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("unable to open %s\n%w", source, err)
+	}
+	defer in.Close()
+
+	if err := sherpa.CopyFile(in, destination); err != nil {
+		return fmt.Errorf("unable to copy %s to %s\n%w", source, destination, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/magiconair/properties"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testThinLauncher(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		path       string
+		repository string
+	)
+
+	var Artifact = func(c boot.MavenCoordinates, pom string) {
+		file := filepath.Join(repository, c.Path())
+		Expect(os.MkdirAll(filepath.Dir(file), 0755)).To(Succeed())
+		Expect(os.WriteFile(file, []byte{}, 0644)).To(Succeed())
+
+		if pom != "" {
+			c.Type = "pom"
+			Expect(os.WriteFile(filepath.Join(repository, c.Path()), []byte(pom), 0644)).To(Succeed())
+		}
+	}
+
+	it.Before(func() {
+		var err error
+
+		path, err = os.MkdirTemp("", "thin-application")
+		Expect(err).NotTo(HaveOccurred())
+
+		repository, err = os.MkdirTemp("", "thin-repository")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(path, "META-INF"), 0755)).To(Succeed())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(path)).To(Succeed())
+		Expect(os.RemoveAll(repository)).To(Succeed())
+	})
+
+	context("detection", func() {
+		it("detects the thin jar wrapper", func() {
+			m := properties.LoadMap(map[string]string{"Main-Class": boot.ThinJarWrapperClass})
+			Expect(boot.IsThinApplication(path, m)).To(BeTrue())
+		})
+
+		it("detects thin.properties", func() {
+			Expect(os.WriteFile(filepath.Join(path, "META-INF", "thin.properties"), []byte{}, 0644)).To(Succeed())
+			Expect(boot.IsThinApplication(path, properties.NewProperties())).To(BeTrue())
+		})
+
+		it("does not detect other applications", func() {
+			Expect(boot.IsThinApplication(path, properties.NewProperties())).To(BeFalse())
+		})
+	})
+
+	context("repository", func() {
+		it("uses a maven-repository binding path", func() {
+			binds := libcnb.Bindings{libcnb.NewBinding("repo", "/bindings/repo", map[string]string{"type": "maven-repository", "path": "/some/repository"})}
+			Expect(boot.ThinRepository(binds, path)).To(Equal("/some/repository"))
+		})
+
+		it("uses a maven-repository binding as the repository", func() {
+			binds := libcnb.Bindings{libcnb.NewBinding("repo", "/bindings/repo", map[string]string{"type": "maven-repository"})}
+			Expect(boot.ThinRepository(binds, path)).To(Equal("/bindings/repo"))
+		})
+
+		it("uses BP_SPRING_BOOT_THIN_REPOSITORY relative to the application", func() {
+			t.Setenv("BP_SPRING_BOOT_THIN_REPOSITORY", ".m2/repository")
+			Expect(boot.ThinRepository(libcnb.Bindings{}, path)).To(Equal(filepath.Join(path, ".m2", "repository")))
+		})
+
+		it("fails without a repository", func() {
+			_, err := boot.ThinRepository(libcnb.Bindings{}, path)
+			Expect(err).To(MatchError(ContainSubstring("maven-repository")))
+		})
+	})
+
+	context("resolution", func() {
+		var manifest *properties.Properties
+
+		it.Before(func() {
+			manifest = properties.LoadMap(map[string]string{
+				"Main-Class":  boot.ThinJarWrapperClass,
+				"Start-Class": "com.example.Application",
+			})
+		})
+
+		it("resolves computed thin.properties", func() {
+			Expect(os.WriteFile(filepath.Join(path, "META-INF", "thin.properties"), []byte(`computed=true
+dependencies.spring-boot=org.springframework.boot:spring-boot:3.2.1
+dependencies.spring-core=org.springframework:spring-core:6.1.2
+`), 0644)).To(Succeed())
+			Artifact(boot.MavenCoordinates{GroupID: "org.springframework.boot", ArtifactID: "spring-boot", Version: "3.2.1"}, "")
+			Artifact(boot.MavenCoordinates{GroupID: "org.springframework", ArtifactID: "spring-core", Version: "6.1.2"}, "")
+
+			e, err := boot.NewThinApplication(path, manifest, repository).Resolve()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(e.Version).To(Equal("3.2.1"))
+			Expect(e.MainClass).To(Equal("com.example.Application"))
+			Expect(e.ClasspathString()).To(Equal(".:lib/*"))
+			Expect(filepath.Join(path, "lib", "spring-boot-3.2.1.jar")).To(BeARegularFile())
+			Expect(filepath.Join(path, "lib", "spring-core-6.1.2.jar")).To(BeARegularFile())
+		})
+
+		it("resolves pom.xml dependencies transitively", func() {
+			Expect(os.MkdirAll(filepath.Join(path, "META-INF", "maven", "com.example", "demo"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(path, "META-INF", "maven", "com.example", "demo", "pom.xml"), []byte(`<project>
+<groupId>com.example</groupId><artifactId>demo</artifactId><version>1</version>
+<dependencies>
+<dependency><groupId>org.springframework.boot</groupId><artifactId>spring-boot</artifactId><version>3.2.1</version></dependency>
+<dependency><groupId>org.junit</groupId><artifactId>junit</artifactId><version>5</version><scope>test</scope></dependency>
+</dependencies>
+</project>`), 0644)).To(Succeed())
+			Artifact(boot.MavenCoordinates{GroupID: "org.springframework.boot", ArtifactID: "spring-boot", Version: "3.2.1"}, `<project>
+<groupId>org.springframework.boot</groupId><artifactId>spring-boot</artifactId><version>3.2.1</version>
+<dependencies>
+<dependency><groupId>org.springframework</groupId><artifactId>spring-core</artifactId><version>6.1.2</version></dependency>
+</dependencies>
+</project>`)
+			Artifact(boot.MavenCoordinates{GroupID: "org.springframework", ArtifactID: "spring-core", Version: "6.1.2"}, "")
+
+			e, err := boot.NewThinApplication(path, manifest, repository).Resolve()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(e.Version).To(Equal("3.2.1"))
+			Expect(filepath.Join(path, "lib", "spring-core-6.1.2.jar")).To(BeARegularFile())
+			Expect(filepath.Join(path, "lib", "junit-5.jar")).NotTo(BeAnExistingFile())
+		})
+
+		it("applies thin.properties exclusions", func() {
+			Expect(os.WriteFile(filepath.Join(path, "META-INF", "thin.properties"), []byte(`computed=true
+dependencies.spring-boot=org.springframework.boot:spring-boot:3.2.1
+dependencies.spring-core=org.springframework:spring-core:6.1.2
+exclusions.spring-core=org.springframework:spring-core
+`), 0644)).To(Succeed())
+			Artifact(boot.MavenCoordinates{GroupID: "org.springframework.boot", ArtifactID: "spring-boot", Version: "3.2.1"}, "")
+
+			_, err := boot.NewThinApplication(path, manifest, repository).Resolve()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(path, "lib", "spring-core-6.1.2.jar")).NotTo(BeAnExistingFile())
+		})
+
+		it("fails when dependencies are missing from the repository", func() {
+			Expect(os.WriteFile(filepath.Join(path, "META-INF", "thin.properties"), []byte(`computed=true
+dependencies.spring-boot=org.springframework.boot:spring-boot:3.2.1
+`), 0644)).To(Succeed())
+
+			_, err := boot.NewThinApplication(path, manifest, repository).Resolve()
+			Expect(err).To(MatchError(ContainSubstring("org.springframework.boot:spring-boot:3.2.1")))
+		})
+	})
+}
@@ -0,0 +1,664 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/paketo-buildpacks/libpak/bindings"
+)
+
+const (
+	// BindingTypeVulnerabilityDB is the type of the binding providing a vulnerability database in OSV format.
+	BindingTypeVulnerabilityDB = "vulnerability-db"
+
+	// VulnerabilityReportLayer is the name of the launch layer holding the vulnerability report.
+	VulnerabilityReportLayer = "vulnerability-report"
+)
+
+// Severity is the qualitative severity of a vulnerability.
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = map[Severity]string{
+	SeverityUnknown:  "unknown",
+	SeverityLow:      "low",
+	SeverityMedium:   "medium",
+	SeverityHigh:     "high",
+	SeverityCritical: "critical",
+}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// ParseSeverity parses a severity name, accepting moderate as used by GitHub advisories for medium.
+func ParseSeverity(s string) (Severity, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "moderate" {
+		return SeverityMedium, true
+	}
+	for severity, name := range severityNames {
+		if name == s && severity != SeverityUnknown {
+			return severity, true
+		}
+	}
+	return SeverityUnknown, false
+}
+
+// CVSSSeverity returns the severity of a CVSS base score.
+func CVSSSeverity(score float64) Severity {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	default:
+		return SeverityUnknown
+	}
+}
+
+// ParseSeverityPolicy parses the policy, configured by name, deciding which findings fail the build: warn, which never
+// fails, or fail-on=<severity>, which fails on findings of that severity or higher. It returns the lowest severity
+// failing the build, or SeverityUnknown when none does.
+func ParseSeverityPolicy(name string, s string) (Severity, error) {
+	policy := strings.ToLower(strings.TrimSpace(s))
+	if policy == "" || policy == "warn" {
+		return SeverityUnknown, nil
+	}
+
+	if threshold, ok := strings.CutPrefix(policy, "fail-on="); ok {
+		if severity, ok := ParseSeverity(threshold); ok {
+			return severity, nil
+		}
+	}
+
+	return SeverityUnknown, fmt.Errorf("invalid $%s %s, must be warn or fail-on=<critical|high|medium|low>", name, s)
+}
+
+// VulnerabilityDatabasePath returns the location of the vulnerability database. A binding of type vulnerability-db
+// takes precedence over path, the value of BP_SPRING_BOOT_VULN_DB. The binding either contains a path entry pointing to
+// the database, or is the database itself.
+func VulnerabilityDatabasePath(binds libcnb.Bindings, appPath string, path string) (string, bool, error) {
+	if b, ok, err := bindings.ResolveOne(binds, bindings.OfType(BindingTypeVulnerabilityDB)); err != nil {
+		return "", false, fmt.Errorf("unable to resolve binding %s\n%w", BindingTypeVulnerabilityDB, err)
+	} else if ok {
+		if p, ok := b.Secret["path"]; ok {
+			return strings.TrimSpace(p), true, nil
+		}
+		return b.Path, true, nil
+	}
+
+	if p := strings.TrimSpace(path); p != "" {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(appPath, p)
+		}
+		return p, true, nil
+	}
+
+	return "", false, nil
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+}
+
+type osvRange struct {
+	Type   string     `json:"type"`
+	Events []osvEvent `json:"events"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges   []osvRange `json:"ranges"`
+	Versions []string   `json:"versions"`
+}
+
+type osvSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type osvRecord struct {
+	ID               string        `json:"id"`
+	Aliases          []string      `json:"aliases"`
+	Summary          string        `json:"summary"`
+	Severity         []osvSeverity `json:"severity"`
+	Affected         []osvAffected `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// VulnerabilityDatabase is the set of advisories of the Maven ecosystem, indexed by group:artifact.
+type VulnerabilityDatabase struct {
+	advisories map[string][]osvRecord
+}
+
+// LoadVulnerabilityDatabase reads advisories in OSV JSON format from path, which is a directory of JSON files, a zip
+// of JSON files as published by OSV, or a single JSON file holding one advisory or an array of advisories.
+func LoadVulnerabilityDatabase(path string) (VulnerabilityDatabase, error) {
+	db := VulnerabilityDatabase{advisories: make(map[string][]osvRecord)}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return VulnerabilityDatabase{}, fmt.Errorf("unable to stat %s\n%w", path, err)
+	}
+
+	switch {
+	case fi.IsDir():
+		err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || filepath.Ext(p) != ".json" {
+				return err
+			}
+			b, err := os.ReadFile(p)
+			if err != nil {
+				return fmt.Errorf("unable to read %s\n%w", p, err)
+			}
+			return db.add(p, b)
+		})
+	case filepath.Ext(path) == ".zip":
+		err = db.addZip(path)
+	default:
+		var b []byte
+		if b, err = os.ReadFile(path); err != nil {
+			return VulnerabilityDatabase{}, fmt.Errorf("unable to read %s\n%w", path, err)
+		}
+		err = db.add(path, b)
+	}
+	if err != nil {
+		return VulnerabilityDatabase{}, err
+	}
+
+	return db, nil
+}
+
+func (v VulnerabilityDatabase) addZip(path string) error {
+	in, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("unable to open %s\n%w", path, err)
+	}
+	defer in.Close()
+
+	for _, f := range in.File {
+		if f.FileInfo().IsDir() || filepath.Ext(f.Name) != ".json" {
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("unable to open %s in %s\n%w", f.Name, path, err)
+		}
+		b, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return fmt.Errorf("unable to read %s in %s\n%w", f.Name, path, err)
+		}
+
+		if err := v.add(f.Name, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v VulnerabilityDatabase) add(name string, b []byte) error {
+	var records []osvRecord
+	if s := strings.TrimSpace(string(b)); strings.HasPrefix(s, "[") {
+		if err := json.Unmarshal(b, &records); err != nil {
+			return fmt.Errorf("unable to decode %s\n%w", name, err)
+		}
+	} else {
+		var r osvRecord
+		if err := json.Unmarshal(b, &r); err != nil {
+			return fmt.Errorf("unable to decode %s\n%w", name, err)
+		}
+		records = append(records, r)
+	}
+
+	for _, r := range records {
+		seen := make(map[string]bool)
+		for _, a := range r.Affected {
+			if !strings.EqualFold(a.Package.Ecosystem, "Maven") || seen[a.Package.Name] {
+				continue
+			}
+			seen[a.Package.Name] = true
+			v.advisories[a.Package.Name] = append(v.advisories[a.Package.Name], r)
+		}
+	}
+
+	return nil
+}
+
+// Vulnerability is an advisory affecting a dependency.
+type Vulnerability struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Summary  string   `json:"summary,omitempty"`
+	Severity Severity `json:"severity"`
+	Score    float64  `json:"score,omitempty"`
+}
+
+// CVE returns the CVE identifier of the vulnerability, or its advisory identifier when it has none.
+func (v Vulnerability) CVE() string {
+	if strings.HasPrefix(v.ID, "CVE-") {
+		return v.ID
+	}
+	for _, a := range v.Aliases {
+		if strings.HasPrefix(a, "CVE-") {
+			return a
+		}
+	}
+	return v.ID
+}
+
+// VulnerabilityFinding is a dependency affected by vulnerabilities.
+type VulnerabilityFinding struct {
+	Group           string          `json:"group"`
+	Name            string          `json:"name"`
+	Version         string          `json:"version"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+}
+
+func (f VulnerabilityFinding) String() string {
+	s := make([]string, len(f.Vulnerabilities))
+	for i, v := range f.Vulnerabilities {
+		s[i] = fmt.Sprintf("%s (%s)", v.CVE(), v.Severity)
+	}
+	return fmt.Sprintf("%s:%s:%s: %s", f.Group, f.Name, f.Version, strings.Join(s, ", "))
+}
+
+// Severity returns the highest severity of the vulnerabilities of the finding.
+func (f VulnerabilityFinding) Severity() Severity {
+	max := SeverityUnknown
+	for _, v := range f.Vulnerabilities {
+		if v.Severity > max {
+			max = v.Severity
+		}
+	}
+	return max
+}
+
+// Match returns the dependencies affected by advisories of the database. Dependencies without a known group cannot
+// be matched against Maven advisories and are skipped.
+func (v VulnerabilityDatabase) Match(dependencies []Dependency) []VulnerabilityFinding {
+	var findings []VulnerabilityFinding
+
+	for _, d := range dependencies {
+		if d.Group == "" || d.Version == "" {
+			continue
+		}
+
+		name := fmt.Sprintf("%s:%s", d.Group, d.Name)
+		var vulnerabilities []Vulnerability
+		for _, r := range v.advisories[name] {
+			if r.affects(name, d.Version) {
+				vulnerabilities = append(vulnerabilities, r.vulnerability())
+			}
+		}
+		if len(vulnerabilities) == 0 {
+			continue
+		}
+
+		sort.Slice(vulnerabilities, func(i, j int) bool {
+			if vulnerabilities[i].Severity != vulnerabilities[j].Severity {
+				return vulnerabilities[i].Severity > vulnerabilities[j].Severity
+			}
+			return vulnerabilities[i].ID < vulnerabilities[j].ID
+		})
+		findings = append(findings, VulnerabilityFinding{Group: d.Group, Name: d.Name, Version: d.Version, Vulnerabilities: vulnerabilities})
+	}
+
+	return findings
+}
+
+func (r osvRecord) affects(name string, version string) bool {
+	for _, a := range r.Affected {
+		if !strings.EqualFold(a.Package.Ecosystem, "Maven") || a.Package.Name != name {
+			continue
+		}
+
+		for _, v := range a.Versions {
+			if CompareMavenVersions(v, version) == 0 {
+				return true
+			}
+		}
+
+		for _, rg := range a.Ranges {
+			if rg.Type != "ECOSYSTEM" && rg.Type != "SEMVER" {
+				continue
+			}
+			if inRange(rg.Events, version) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// inRange evaluates the introduced, fixed and last_affected events of an OSV range in order.
+func inRange(events []osvEvent, version string) bool {
+	introduced := ""
+	open := false
+
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			introduced, open = e.Introduced, true
+		case e.Fixed != "" && open:
+			if after(version, introduced) && CompareMavenVersions(version, e.Fixed) < 0 {
+				return true
+			}
+			open = false
+		case e.LastAffected != "" && open:
+			if after(version, introduced) && CompareMavenVersions(version, e.LastAffected) <= 0 {
+				return true
+			}
+			open = false
+		}
+	}
+
+	return open && after(version, introduced)
+}
+
+func after(version string, introduced string) bool {
+	return introduced == "0" || CompareMavenVersions(version, introduced) >= 0
+}
+
+func (r osvRecord) vulnerability() Vulnerability {
+	v := Vulnerability{ID: r.ID, Aliases: r.Aliases, Summary: r.Summary}
+
+	for _, s := range r.Severity {
+		if !strings.HasPrefix(s.Type, "CVSS_V3") {
+			continue
+		}
+		if score, ok := CVSSScore(s.Score); ok {
+			v.Score = score
+			v.Severity = CVSSSeverity(score)
+			break
+		}
+	}
+
+	if s, ok := ParseSeverity(r.DatabaseSpecific.Severity); ok {
+		v.Severity = s
+	}
+
+	return v
+}
+
+// CVSSScore returns the base score of a CVSS 3.x vector, or the score itself when it is numeric.
+func CVSSScore(vector string) (float64, bool) {
+	if f, err := strconv.ParseFloat(strings.TrimSpace(vector), 64); err == nil {
+		return f, true
+	}
+
+	metrics := make(map[string]string)
+	for _, m := range strings.Split(vector, "/") {
+		if k, v, ok := strings.Cut(m, ":"); ok {
+			metrics[k] = v
+		}
+	}
+
+	weights := map[string]map[string]float64{
+		"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+		"AC": {"L": 0.77, "H": 0.44},
+		"UI": {"N": 0.85, "R": 0.62},
+		"C":  {"H": 0.56, "L": 0.22, "N": 0},
+		"I":  {"H": 0.56, "L": 0.22, "N": 0},
+		"A":  {"H": 0.56, "L": 0.22, "N": 0},
+	}
+	scopeChanged := metrics["S"] == "C"
+	if scopeChanged {
+		weights["PR"] = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}
+	} else {
+		weights["PR"] = map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}
+	}
+
+	w := make(map[string]float64, len(weights))
+	for k, values := range weights {
+		f, ok := values[metrics[k]]
+		if !ok {
+			return 0, false
+		}
+		w[k] = f
+	}
+
+	iss := 1 - (1-w["C"])*(1-w["I"])*(1-w["A"])
+	impact := 6.42 * iss
+	if scopeChanged {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+
+	score := impact + 8.22*w["AV"]*w["AC"]*w["PR"]*w["UI"]
+	if scopeChanged {
+		score *= 1.08
+	}
+	return math.Ceil(math.Min(score, 10)*10-1e-9) / 10, true
+}
+
+// qualifiers orders the well-known qualifiers of Maven versions, the empty qualifier being a release.
+var qualifiers = map[string]int{
+	"alpha": 1, "a": 1,
+	"beta": 2, "b": 2,
+	"milestone": 3, "m": 3,
+	"rc": 4, "cr": 4,
+	"snapshot": 5,
+	"":         6, "ga": 6, "final": 6, "release": 6,
+	"sp": 7,
+}
+
+// CompareMavenVersions compares two versions following the ordering of Maven, such that 1.0-rc1 < 1.0 = 1.0.0 =
+// 1.0.RELEASE < 1.0-sp1 < 1.0.1.
+func CompareMavenVersions(a string, b string) int {
+	x, y := mavenVersionItems(a), mavenVersionItems(b)
+
+	for i := 0; i < len(x) || i < len(y); i++ {
+		var p, q string
+		if i < len(x) {
+			p = x[i]
+		}
+		if i < len(y) {
+			q = y[i]
+		}
+
+		if c := compareMavenVersionItems(p, q); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func compareMavenVersionItems(p string, q string) int {
+	pn, pErr := strconv.ParseUint(p, 10, 64)
+	qn, qErr := strconv.ParseUint(q, 10, 64)
+
+	switch {
+	case pErr == nil && qErr == nil:
+		return compareInts(pn, qn)
+	case pErr == nil && q == "":
+		return compareInts(pn, 0)
+	case qErr == nil && p == "":
+		return compareInts(0, qn)
+	case pErr == nil:
+		return 1
+	case qErr == nil:
+		return -1
+	}
+
+	pq, pKnown := qualifiers[p]
+	qq, qKnown := qualifiers[q]
+	switch {
+	case pKnown && qKnown:
+		return compareInts(pq, qq)
+	case pKnown:
+		return -1
+	case qKnown:
+		return 1
+	default:
+		return strings.Compare(p, q)
+	}
+}
+
+func compareInts[T int | uint64](a T, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// mavenVersionItems splits a version at separators and at transitions between digits and letters.
+func mavenVersionItems(version string) []string {
+	var items []string
+	var current strings.Builder
+
+	flush := func() {
+		items = append(items, current.String())
+		current.Reset()
+	}
+
+	version = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+	for i, r := range version {
+		if r == '.' || r == '-' || r == '_' || r == '+' {
+			flush()
+			continue
+		}
+
+		if i > 0 && current.Len() > 0 {
+			prev := rune(version[i-1])
+			if isDigit(prev) != isDigit(r) {
+				flush()
+			}
+		}
+		current.WriteRune(r)
+	}
+	flush()
+
+	// trailing zeros and release qualifiers do not change the version
+	for len(items) > 1 {
+		last := items[len(items)-1]
+		if q, ok := qualifiers[last]; (ok && q == qualifiers[""]) || strings.Trim(last, "0") == "" {
+			items = items[:len(items)-1]
+			continue
+		}
+		break
+	}
+
+	return items
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// VulnerabilityReport writes vulnerability findings to a launch layer, so the image documents what it was built with.
+type VulnerabilityReport struct {
+	Database string
+	Findings []VulnerabilityFinding
+}
+
+func (v VulnerabilityReport) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
+	if err := os.MkdirAll(layer.Path, 0755); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to create %s\n%w", layer.Path, err)
+	}
+
+	findings := v.Findings
+	if findings == nil {
+		findings = []VulnerabilityFinding{}
+	}
+	report := map[string]interface{}{"database": v.Database, "findings": findings}
+	if err := writeJSON(filepath.Join(layer.Path, "vulnerabilities.json"), report); err != nil {
+		return libcnb.Layer{}, fmt.Errorf("unable to write vulnerability report\n%w", err)
+	}
+
+	layer.LayerTypes = libcnb.LayerTypes{Launch: true}
+	return layer, nil
+}
+
+func (VulnerabilityReport) Name() string {
+	return VulnerabilityReportLayer
+}
+
+// ReportVulnerabilities matches dependencies against the vulnerability database at path and logs the findings. It
+// returns an error if a finding is of threshold severity or higher, unless threshold is SeverityUnknown.
+func ReportVulnerabilities(logger bard.Logger, path string, dependencies []Dependency, threshold Severity) (VulnerabilityReport, error) {
+	db, err := LoadVulnerabilityDatabase(path)
+	if err != nil {
+		return VulnerabilityReport{}, fmt.Errorf("unable to load vulnerability database\n%w", err)
+	}
+
+	report := VulnerabilityReport{Database: path, Findings: db.Match(dependencies)}
+	if len(report.Findings) == 0 {
+		logger.Bodyf("No known vulnerabilities found in %d dependencies", len(dependencies))
+		return report, nil
+	}
+
+	var all, failing []string
+	for _, f := range report.Findings {
+		all = append(all, f.String())
+		if threshold != SeverityUnknown && f.Severity() >= threshold {
+			failing = append(failing, f.String())
+		}
+	}
+
+	if len(failing) > 0 {
+		return VulnerabilityReport{}, fmt.Errorf("dependencies have vulnerabilities of severity %s or higher:\n  %s", threshold, strings.Join(failing, "\n  "))
+	}
+
+	logger.Header(Warningf("WARNING: dependencies have known vulnerabilities:\n  %s", strings.Join(all, "\n  ")))
+	return report, nil
+}
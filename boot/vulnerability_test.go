@@ -0,0 +1,238 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+const springShellAdvisory = `{
+  "id": "GHSA-36p3-wjmg-h94x",
+  "aliases": ["CVE-2022-22965"],
+  "summary": "Remote Code Execution in Spring Framework",
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.springframework:spring-beans"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "5.2.20.RELEASE"}, {"introduced": "5.3.0"}, {"fixed": "5.3.18"}]}]
+  }],
+  "database_specific": {"severity": "CRITICAL"}
+}`
+
+func testVulnerability(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	context("database", func() {
+		var path string
+
+		it.Before(func() {
+			path = t.TempDir()
+		})
+
+		it("matches dependencies in affected ranges", func() {
+			Expect(os.WriteFile(filepath.Join(path, "GHSA-36p3-wjmg-h94x.json"), []byte(springShellAdvisory), 0644)).To(Succeed())
+
+			db, err := boot.LoadVulnerabilityDatabase(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			findings := db.Match([]boot.Dependency{
+				{Group: "org.springframework", Name: "spring-beans", Version: "5.3.17"},
+				{Group: "org.springframework", Name: "spring-beans", Version: "5.3.18"},
+				{Group: "org.springframework", Name: "spring-beans", Version: "5.2.19.RELEASE"},
+				{Group: "org.springframework", Name: "spring-core", Version: "5.3.17"},
+				{Name: "spring-beans", Version: "5.3.17"},
+			})
+
+			Expect(findings).To(HaveLen(2))
+			Expect(findings[0].Version).To(Equal("5.3.17"))
+			Expect(findings[0].Vulnerabilities).To(Equal([]boot.Vulnerability{{
+				ID:       "GHSA-36p3-wjmg-h94x",
+				Aliases:  []string{"CVE-2022-22965"},
+				Summary:  "Remote Code Execution in Spring Framework",
+				Severity: boot.SeverityCritical,
+				Score:    9.8,
+			}}))
+			Expect(findings[0].String()).To(Equal("org.springframework:spring-beans:5.3.17: CVE-2022-22965 (critical)"))
+			Expect(findings[1].Version).To(Equal("5.2.19.RELEASE"))
+		})
+
+		it("matches listed versions and last affected versions", func() {
+			Expect(os.WriteFile(filepath.Join(path, "advisories.json"), []byte(`[
+  {"id": "CVE-2024-0001", "affected": [{"package": {"ecosystem": "Maven", "name": "com.example:demo"}, "versions": ["1.0.0"]}]},
+  {"id": "CVE-2024-0002", "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N"}],
+   "affected": [{"package": {"ecosystem": "Maven", "name": "com.example:demo"}, "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "1.1"}, {"last_affected": "1.2"}]}]}]}
+]`), 0644)).To(Succeed())
+
+			db, err := boot.LoadVulnerabilityDatabase(filepath.Join(path, "advisories.json"))
+			Expect(err).NotTo(HaveOccurred())
+
+			findings := db.Match([]boot.Dependency{
+				{Group: "com.example", Name: "demo", Version: "1.0"},
+				{Group: "com.example", Name: "demo", Version: "1.2.0"},
+				{Group: "com.example", Name: "demo", Version: "1.2.1"},
+			})
+
+			Expect(findings).To(HaveLen(2))
+			Expect(findings[0].Vulnerabilities[0].ID).To(Equal("CVE-2024-0001"))
+			Expect(findings[0].Severity()).To(Equal(boot.SeverityUnknown))
+			Expect(findings[1].Vulnerabilities[0].ID).To(Equal("CVE-2024-0002"))
+			Expect(findings[1].Severity()).To(Equal(boot.SeverityLow))
+		})
+
+		it("reads zip archives", func() {
+			out, err := os.Create(filepath.Join(path, "all.zip"))
+			Expect(err).NotTo(HaveOccurred())
+			z := zip.NewWriter(out)
+			w, err := z.Create("GHSA-36p3-wjmg-h94x.json")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte(springShellAdvisory))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(z.Close()).To(Succeed())
+			Expect(out.Close()).To(Succeed())
+
+			db, err := boot.LoadVulnerabilityDatabase(filepath.Join(path, "all.zip"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(db.Match([]boot.Dependency{{Group: "org.springframework", Name: "spring-beans", Version: "5.3.0"}})).To(HaveLen(1))
+		})
+
+		context("report", func() {
+			var (
+				dependencies []boot.Dependency
+				out          *bytes.Buffer
+			)
+
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(path, "GHSA-36p3-wjmg-h94x.json"), []byte(springShellAdvisory), 0644)).To(Succeed())
+				dependencies = []boot.Dependency{{Group: "org.springframework", Name: "spring-beans", Version: "5.3.17"}}
+				out = &bytes.Buffer{}
+			})
+
+			it("warns about vulnerable dependencies", func() {
+				report, err := boot.ReportVulnerabilities(bard.NewLogger(out), path, dependencies, boot.SeverityUnknown)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(report.Findings).To(HaveLen(1))
+				Expect(out.String()).To(ContainSubstring("org.springframework:spring-beans:5.3.17: CVE-2022-22965 (critical)"))
+			})
+
+			it("fails at or above the threshold", func() {
+				_, err := boot.ReportVulnerabilities(bard.NewLogger(out), path, dependencies, boot.SeverityCritical)
+				Expect(err).To(MatchError("dependencies have vulnerabilities of severity critical or higher:\n  org.springframework:spring-beans:5.3.17: CVE-2022-22965 (critical)"))
+			})
+
+			it("reports dependencies without known vulnerabilities", func() {
+				report, err := boot.ReportVulnerabilities(bard.NewLogger(out), path, nil, boot.SeverityLow)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(report.Findings).To(BeEmpty())
+				Expect(out.String()).To(ContainSubstring("No known vulnerabilities found in 0 dependencies"))
+			})
+		})
+
+		it("fails on malformed advisories", func() {
+			Expect(os.WriteFile(filepath.Join(path, "broken.json"), []byte("{"), 0644)).To(Succeed())
+
+			_, err := boot.LoadVulnerabilityDatabase(path)
+			Expect(err).To(MatchError(ContainSubstring("unable to decode")))
+		})
+	})
+
+	context("VulnerabilityDatabasePath", func() {
+		it("prefers the binding", func() {
+			binds := libcnb.Bindings{libcnb.NewBinding("osv", "/bindings/osv", map[string]string{"type": "vulnerability-db"})}
+
+			path, ok, err := boot.VulnerabilityDatabasePath(binds, "/workspace", "osv")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(path).To(Equal("/bindings/osv"))
+		})
+
+		it("resolves the path against the application", func() {
+			path, ok, err := boot.VulnerabilityDatabasePath(nil, "/workspace", "osv")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(path).To(Equal("/workspace/osv"))
+		})
+
+		it("is disabled without database", func() {
+			_, ok, err := boot.VulnerabilityDatabasePath(nil, "/workspace", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	it("parses the policy", func() {
+		Expect(boot.ParseSeverityPolicy("BP_SPRING_BOOT_VULN_POLICY", "")).To(Equal(boot.SeverityUnknown))
+		Expect(boot.ParseSeverityPolicy("BP_SPRING_BOOT_VULN_POLICY", "warn")).To(Equal(boot.SeverityUnknown))
+		Expect(boot.ParseSeverityPolicy("BP_SPRING_BOOT_VULN_POLICY", "fail-on=critical")).To(Equal(boot.SeverityCritical))
+		Expect(boot.ParseSeverityPolicy("BP_SPRING_BOOT_VULN_POLICY", "fail-on=Moderate")).To(Equal(boot.SeverityMedium))
+
+		_, err := boot.ParseSeverityPolicy("BP_SPRING_BOOT_VULN_POLICY", "fail")
+		Expect(err).To(MatchError("invalid $BP_SPRING_BOOT_VULN_POLICY fail, must be warn or fail-on=<critical|high|medium|low>"))
+	})
+
+	it("computes CVSS 3 base scores", func() {
+		for vector, expected := range map[string]float64{
+			"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H": 9.8,
+			"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H": 10.0,
+			"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:U/C:N/I:N/A:H": 6.5,
+			"7.5": 7.5,
+		} {
+			score, ok := boot.CVSSScore(vector)
+			Expect(ok).To(BeTrue())
+			Expect(score).To(Equal(expected), vector)
+		}
+
+		_, ok := boot.CVSSScore("CVSS:3.1/AV:X")
+		Expect(ok).To(BeFalse())
+	})
+
+	it("compares Maven versions", func() {
+		Expect(boot.CompareMavenVersions("1.0", "1.0.0")).To(Equal(0))
+		Expect(boot.CompareMavenVersions("5.2.20.RELEASE", "5.2.20")).To(Equal(0))
+		Expect(boot.CompareMavenVersions("1.0-rc1", "1.0")).To(Equal(-1))
+		Expect(boot.CompareMavenVersions("1.0-M2", "1.0-RC1")).To(Equal(-1))
+		Expect(boot.CompareMavenVersions("1.0-SNAPSHOT", "1.0")).To(Equal(-1))
+		Expect(boot.CompareMavenVersions("1.0-sp1", "1.0")).To(Equal(1))
+		Expect(boot.CompareMavenVersions("1.0.1", "1.0-sp1")).To(Equal(1))
+		Expect(boot.CompareMavenVersions("1.10", "1.9")).To(Equal(1))
+		Expect(boot.CompareMavenVersions("2.13.4.2", "2.13.4")).To(Equal(1))
+	})
+
+	it("writes the report", func() {
+		layer := libcnb.Layer{Path: t.TempDir()}
+
+		layer, err := boot.VulnerabilityReport{Database: "/bindings/osv"}.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LayerTypes.Launch).To(BeTrue())
+		Expect(os.ReadFile(filepath.Join(layer.Path, "vulnerabilities.json"))).To(MatchJSON(`{"database": "/bindings/osv", "findings": []}`))
+	})
+}
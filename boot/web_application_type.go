@@ -17,29 +17,76 @@
 package boot
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
 	"github.com/buildpacks/libcnb"
 	"github.com/paketo-buildpacks/libpak"
 	"github.com/paketo-buildpacks/libpak/bard"
-	"github.com/paketo-buildpacks/libpak/sherpa"
 )
 
+const (
+	// DefaultThreadCount covers the platform threads of a non-blocking or non-web application.
+	DefaultThreadCount = 50
+
+	// DefaultServletThreadCount covers the default request pool of 200 threads of an embedded servlet container.
+	DefaultServletThreadCount = 250
+
+	// NettyHeadRoom is the percentage of memory left for the direct buffers Netty allocates outside the heap.
+	NettyHeadRoom = 10
+)
+
+// serverThreadPoolKeys lists the properties configuring the request thread pool of each embedded server, current
+// name first.
+var serverThreadPoolKeys = map[string][]string{
+	"tomcat":   {"server.tomcat.threads.max", "server.tomcat.max-threads"},
+	"jetty":    {"server.jetty.threads.max", "server.jetty.max-threads"},
+	"undertow": {"server.undertow.threads.worker", "server.undertow.worker-threads"},
+}
+
 type WebApplicationType struct {
 	LayerContributor libpak.LayerContributor
 	Logger           bard.Logger
 	Resolver         WebApplicationTypeResolver
+
+	// JavaVersion is the Java release the application runs on, used to determine whether virtual threads are
+	// available.
+	JavaVersion int
+
+	// LoadedClassCount is the estimated number of classes loaded at runtime, contributed as a hint to the memory
+	// calculator when greater than 0.
+	LoadedClassCount int
 }
 
-func NewWebApplicationType(applicationPath string, resolver WebApplicationTypeResolver) (WebApplicationType, error) {
-	files, err := sherpa.NewFileListing(applicationPath)
-	if err != nil {
-		return WebApplicationType{}, fmt.Errorf("unable to create file listing for %s\n%w", applicationPath, err)
+// NewWebApplicationType creates a contributor whose cache key is built only from the inputs that can change its
+// result: the digests of the indexed jars, the indicator classes present and the relevant configuration properties.
+func NewWebApplicationType(index JarIndex, resolver WebApplicationTypeResolver) WebApplicationType {
+	jars := make(map[string]interface{}, len(index.Jars))
+	for _, e := range index.Jars {
+		jars[filepath.Base(e.Path)] = e.SHA256
+	}
+
+	var indicators []string
+	for _, class := range webApplicationTypeIndicatorClasses() {
+		if resolver.isPresent(class) {
+			indicators = append(indicators, class)
+		}
+	}
+
+	configuration := make(map[string]interface{})
+	for _, key := range webApplicationTypeConfigurationKeys() {
+		if v, ok := resolver.Configuration.Get(key); ok {
+			configuration[key] = v
+		}
 	}
 
-	expected := make(map[string]interface{}, 1)
-	expected["files"] = calculateHash(files)
+	expected := map[string]interface{}{
+		"jars":          jars,
+		"indicators":    indicators,
+		"configuration": configuration,
+	}
 
 	contributor := libpak.NewLayerContributor(
 		"Web Application Type",
@@ -51,39 +98,150 @@ func NewWebApplicationType(applicationPath string, resolver WebApplicationTypeRe
 	return WebApplicationType{
 		LayerContributor: contributor,
 		Resolver:         resolver,
-	}, nil
+	}
 }
 
-func calculateHash(files []sherpa.FileEntry) string {
-	hash := sha256.New()
-
-	for _, file := range files {
-		hash.Write([]byte(file.Path + file.Mode + file.SHA256 + "\n"))
+func webApplicationTypeIndicatorClasses() []string {
+	classes := []string{WebMVCIndicatorClass, WebFluxIndicatorClass, JerseyIndicatorClass, ConfigurableWebApplicationContextIndicatorClass}
+	classes = append(classes, ServletIndicatorClasses...)
+	for _, s := range ReactiveServers {
+		classes = append(classes, s.Class)
 	}
+	for _, w := range []Workload{GRPCWorkload, RSocketWorkload, MessagingWorkload} {
+		classes = append(classes, WorkloadIndicatorClasses[w]...)
+	}
+	return classes
+}
 
-	return hex.EncodeToString(hash.Sum(nil))
+func webApplicationTypeConfigurationKeys() []string {
+	keys := []string{WebApplicationTypeConfigurationKey, "spring.threads.virtual.enabled"}
+	for _, s := range ServletServers {
+		keys = append(keys, serverThreadPoolKeys[s.Name]...)
+	}
+	return keys
 }
 
 func (w WebApplicationType) Contribute(layer libcnb.Layer) (libcnb.Layer, error) {
 	w.LayerContributor.Logger = w.Logger
 
+	threads, reason := w.ThreadCount()
+	if expected, ok := w.LayerContributor.ExpectedMetadata.(map[string]interface{}); ok {
+		expected["thread-count"] = threads
+		expected["loaded-class-count"] = w.LoadedClassCount
+	}
+
+	source := w.Resolver.Source()
+	switch w.Resolver.Resolve() {
+	case None:
+		w.Logger.Bodyf("Non-web application detected from %s", source)
+	case Reactive:
+		w.Logger.Bodyf("Reactive web application detected from %s", source)
+	case Servlet:
+		w.Logger.Bodyf("Servlet web application detected from %s", source)
+	}
+
 	return w.LayerContributor.Contribute(layer, func() (libcnb.Layer, error) {
-		switch w.Resolver.Resolve() {
-		case None:
-			w.Logger.Body("Non-web application detected")
-			layer.LaunchEnvironment.Default("BPL_JVM_THREAD_COUNT", "50")
-		case Reactive:
-			w.Logger.Body("Reactive web application detected")
-			layer.LaunchEnvironment.Default("BPL_JVM_THREAD_COUNT", "50")
-		case Servlet:
-			w.Logger.Body("Servlet web application detected")
-			layer.LaunchEnvironment.Default("BPL_JVM_THREAD_COUNT", "250")
+		server, serverFound := w.Resolver.Server()
+		if serverFound {
+			w.Logger.Bodyf("Embedded %s server detected", server.Name)
+		}
+
+		if server == Netty {
+			w.Logger.Bodyf("Reserving %d%% head room for Netty direct memory", NettyHeadRoom)
+			layer.LaunchEnvironment.Default("BPL_JVM_HEAD_ROOM", strconv.Itoa(NettyHeadRoom))
+		}
+
+		if reason != "" {
+			w.Logger.Bodyf("Estimating %d threads from %s", threads, reason)
+		}
+		layer.LaunchEnvironment.Default("BPL_JVM_THREAD_COUNT", strconv.Itoa(threads))
+
+		if w.LoadedClassCount > 0 {
+			w.Logger.Bodyf("Estimating %d loaded classes", w.LoadedClassCount)
+			layer.LaunchEnvironment.Default("BPL_JVM_LOADED_CLASS_COUNT", strconv.Itoa(w.LoadedClassCount))
 		}
 
 		return layer, nil
 	})
 }
 
+// ThreadCount estimates the number of platform threads the application uses, along with the configuration and
+// workloads it was derived from when not a default.
+func (w WebApplicationType) ThreadCount() (int, string) {
+	threads, reason := w.requestThreadCount()
+
+	var reasons []string
+	if reason != "" {
+		reasons = append(reasons, reason)
+	}
+
+	for _, workload := range w.Resolver.Workloads() {
+		if n := WorkloadThreads[workload]; n > 0 {
+			threads += n
+			reasons = append(reasons, fmt.Sprintf("the %s workload", workload))
+		}
+	}
+
+	return threads, strings.Join(reasons, " and ")
+}
+
+func (w WebApplicationType) requestThreadCount() (int, string) {
+	if w.Resolver.Resolve() != Servlet {
+		return DefaultThreadCount, ""
+	}
+
+	if w.virtualThreads() {
+		return DefaultThreadCount, "spring.threads.virtual.enabled"
+	}
+
+	if max, key, ok := w.serverThreadPool(); ok {
+		return DefaultThreadCount + max, key
+	}
+
+	if s, ok := w.Resolver.Server(); ok && s.DefaultThreads > 0 {
+		return DefaultThreadCount + s.DefaultThreads, fmt.Sprintf("the default %s thread pool", s.Name)
+	}
+
+	return DefaultServletThreadCount, ""
+}
+
+// virtualThreads returns whether requests are served on virtual threads, which requires Java 21.
+func (w WebApplicationType) virtualThreads() bool {
+	if w.JavaVersion < 21 {
+		return false
+	}
+
+	s, ok := w.Resolver.Configuration.Get("spring.threads.virtual.enabled")
+	if !ok {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(strings.TrimSpace(s))
+	return err == nil && enabled
+}
+
+func (w WebApplicationType) serverThreadPool() (int, string, bool) {
+	servers := []string{Tomcat.Name, Jetty.Name, Undertow.Name}
+	if s, ok := w.Resolver.Server(); ok {
+		servers = []string{s.Name}
+	}
+
+	for _, server := range servers {
+		for _, key := range serverThreadPoolKeys[server] {
+			s, ok := w.Resolver.Configuration.Get(key)
+			if !ok {
+				continue
+			}
+
+			if max, err := strconv.Atoi(strings.TrimSpace(s)); err == nil && max > 0 {
+				return max, key, true
+			}
+		}
+	}
+
+	return 0, "", false
+}
+
 func (WebApplicationType) Name() string {
 	return "web-application-type"
 }
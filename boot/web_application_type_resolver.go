@@ -17,12 +17,10 @@
 package boot
 
 import (
-	"archive/zip"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"sync"
 )
 
 type ApplicationType uint8
@@ -47,18 +45,28 @@ var ServletIndicatorClasses = []string{
 	JakartaServlet,
 }
 
+// WebApplicationTypeConfigurationKey explicitly sets the application type, overriding classpath detection.
+const WebApplicationTypeConfigurationKey = "spring.main.web-application-type"
+
 type WebApplicationTypeResolver struct {
-	Classes map[string]interface{}
+	Classes       map[string]interface{}
+	Configuration ApplicationConfiguration
 }
 
-type result struct {
-	err   error
-	value []string
+func NewWebApplicationResolver(classes string, lib string) (WebApplicationTypeResolver, error) {
+	index, err := NewJarIndexFromDirectory(lib, nil)
+	if err != nil {
+		return WebApplicationTypeResolver{}, fmt.Errorf("unable to list classes\n%w", err)
+	}
+
+	return NewWebApplicationResolverFromIndex(classes, index)
 }
 
-func NewWebApplicationResolver(classes string, lib string) (WebApplicationTypeResolver, error) {
+// NewWebApplicationResolverFromIndex creates a resolver from the classes in the classes directory and the jars of an
+// existing index.
+func NewWebApplicationResolverFromIndex(classes string, index JarIndex) (WebApplicationTypeResolver, error) {
 	w := WebApplicationTypeResolver{
-		Classes: make(map[string]interface{}),
+		Classes: index.Classes(),
 	}
 
 	if err := filepath.Walk(classes, func(path string, info os.FileInfo, err error) error {
@@ -84,59 +92,25 @@ func NewWebApplicationResolver(classes string, lib string) (WebApplicationTypeRe
 		return WebApplicationTypeResolver{}, fmt.Errorf("unable to find class names in %s\n%w", classes, err)
 	}
 
-	jars, err := filepath.Glob(filepath.Join(lib, "*.jar"))
-	if err != nil {
-		return WebApplicationTypeResolver{}, fmt.Errorf("unable to glob %s/*.jar\n%w", lib, err)
-	}
-
-	results := make(chan result)
-	var wg sync.WaitGroup
-	for _, jar := range jars {
-
-		wg.Add(1)
-		go func(jar string) {
-			defer wg.Done()
-
-			in, err := zip.OpenReader(jar)
-			if err != nil {
-				results <- result{err: fmt.Errorf("unable to open %s\n%w", jar, err)}
-				return
-			}
-			defer in.Close()
-
-			var classes []string
-			for _, f := range in.File {
-				if f.FileInfo().IsDir() || filepath.Ext(f.Name) != ".class" {
-					continue
-				}
-
-				class := strings.TrimSuffix(f.Name, ".class")
-				class = strings.ReplaceAll(class, "/", ".")
-				classes = append(classes, class)
-			}
-
-			results <- result{value: classes}
-		}(jar)
-	}
-
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	return w, nil
+}
 
-	for r := range results {
-		if r.err != nil {
-			return WebApplicationTypeResolver{}, fmt.Errorf("unable to list classes\n%w", r.err)
-		}
-		for _, class := range r.value {
-			w.Classes[class] = class
-		}
+func (a ApplicationType) String() string {
+	switch a {
+	case Reactive:
+		return "reactive"
+	case Servlet:
+		return "servlet"
+	default:
+		return "none"
 	}
-
-	return w, nil
 }
 
 func (w WebApplicationTypeResolver) Resolve() ApplicationType {
+	if t, ok := w.configured(); ok {
+		return t
+	}
+
 	if w.isPresent(WebFluxIndicatorClass) && !w.isPresent(WebMVCIndicatorClass) && !w.isPresent(JerseyIndicatorClass) {
 		return Reactive
 	}
@@ -148,6 +122,51 @@ func (w WebApplicationTypeResolver) Resolve() ApplicationType {
 	return Servlet
 }
 
+// Server returns the embedded server Spring Boot starts for the application type, based on the classes present.
+func (w WebApplicationTypeResolver) Server() (EmbeddedServer, bool) {
+	var candidates []EmbeddedServer
+	switch w.Resolve() {
+	case Reactive:
+		candidates = ReactiveServers
+	case Servlet:
+		candidates = ServletServers
+	}
+
+	for _, s := range candidates {
+		if w.isPresent(s.Class) {
+			return s, true
+		}
+	}
+
+	return EmbeddedServer{}, false
+}
+
+// Source describes what decided the application type returned by Resolve.
+func (w WebApplicationTypeResolver) Source() string {
+	if _, ok := w.configured(); ok {
+		return WebApplicationTypeConfigurationKey
+	}
+	return "classpath"
+}
+
+func (w WebApplicationTypeResolver) configured() (ApplicationType, bool) {
+	s, ok := w.Configuration.Get(WebApplicationTypeConfigurationKey)
+	if !ok {
+		return None, false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "none":
+		return None, true
+	case "reactive":
+		return Reactive, true
+	case "servlet":
+		return Servlet, true
+	default:
+		return None, false
+	}
+}
+
 func (w WebApplicationTypeResolver) isPresent(class string) bool {
 	_, ok := w.Classes[class]
 	return ok
@@ -123,6 +123,98 @@ func testWebApplicationTypeResolver(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	context("configuration", func() {
+
+		var Touch = func(name string) {
+			file := filepath.Join(path, fmt.Sprintf("%s.class", strings.ReplaceAll(name, ".", "/")))
+			Expect(os.MkdirAll(filepath.Dir(file), 0755)).To(Succeed())
+			Expect(os.WriteFile(file, []byte{}, 0644)).To(Succeed())
+		}
+
+		it.Before(func() {
+			Touch(boot.WebMVCIndicatorClass)
+			Touch(boot.JakartaServlet)
+			Touch(boot.ConfigurableWebApplicationContextIndicatorClass)
+		})
+
+		it("uses classpath without configuration", func() {
+			w, err := boot.NewWebApplicationResolver(path, path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(w.Resolve()).To(Equal(boot.Servlet))
+			Expect(w.Source()).To(Equal("classpath"))
+		})
+
+		it("detects the embedded server", func() {
+			Touch(boot.Tomcat.Class)
+			Touch(boot.Jetty.Class)
+
+			w, err := boot.NewWebApplicationResolver(path, path)
+			Expect(err).NotTo(HaveOccurred())
+
+			server, ok := w.Server()
+			Expect(ok).To(BeTrue())
+			Expect(server).To(Equal(boot.Tomcat))
+		})
+
+		it("detects Netty for reactive applications", func() {
+			Touch(boot.Tomcat.Class)
+			Touch(boot.Netty.Class)
+
+			w, err := boot.NewWebApplicationResolver(path, path)
+			Expect(err).NotTo(HaveOccurred())
+			w.Configuration = boot.ApplicationConfiguration{Properties: map[string]string{"spring.main.web-application-type": "reactive"}}
+
+			server, ok := w.Server()
+			Expect(ok).To(BeTrue())
+			Expect(server).To(Equal(boot.Netty))
+		})
+
+		it("does not detect a server for non-web applications", func() {
+			Touch(boot.Tomcat.Class)
+
+			w, err := boot.NewWebApplicationResolver(path, path)
+			Expect(err).NotTo(HaveOccurred())
+			w.Configuration = boot.ApplicationConfiguration{Properties: map[string]string{"spring.main.web-application-type": "none"}}
+
+			_, ok := w.Server()
+			Expect(ok).To(BeFalse())
+		})
+
+		it("prefers spring.main.web-application-type", func() {
+			w, err := boot.NewWebApplicationResolver(path, path)
+			Expect(err).NotTo(HaveOccurred())
+			w.Configuration = boot.ApplicationConfiguration{Properties: map[string]string{"spring.main.web-application-type": "NONE"}}
+
+			Expect(w.Resolve()).To(Equal(boot.None))
+			Expect(w.Source()).To(Equal("spring.main.web-application-type"))
+
+			w.Configuration.Properties["spring.main.web-application-type"] = "reactive"
+			Expect(w.Resolve()).To(Equal(boot.Reactive))
+		})
+
+		it("uses profile specific configuration", func() {
+			Expect(os.WriteFile(filepath.Join(path, "application.properties"), []byte("spring.profiles.active=batch"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(path, "application-batch.yml"), []byte("spring.main.webApplicationType: none"), 0644)).To(Succeed())
+
+			w, err := boot.NewWebApplicationResolver(path, path)
+			Expect(err).NotTo(HaveOccurred())
+			w.Configuration, err = boot.NewApplicationConfiguration(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(w.Resolve()).To(Equal(boot.None))
+		})
+
+		it("ignores invalid values", func() {
+			w, err := boot.NewWebApplicationResolver(path, path)
+			Expect(err).NotTo(HaveOccurred())
+			w.Configuration = boot.ApplicationConfiguration{Properties: map[string]string{"spring.main.web-application-type": "${WEB_TYPE}"}}
+
+			Expect(w.Resolve()).To(Equal(boot.Servlet))
+			Expect(w.Source()).To(Equal("classpath"))
+		})
+	})
+
 	context("lib", func() {
 
 		var Copy = func(name string) {
@@ -17,6 +17,7 @@
 package boot_test
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/buildpacks/libcnb"
 	. "github.com/onsi/gomega"
+	"github.com/paketo-buildpacks/libpak/bard"
 	"github.com/sclevine/spec"
 
 	"github.com/paketo-buildpacks/spring-boot/v5/boot"
@@ -48,12 +50,13 @@ func testWebApplicationType(t *testing.T, context spec.G, it spec.S) {
 
 		wr := boot.WebApplicationTypeResolver{Classes: map[string]interface{}{}}
 
-		w, err = boot.NewWebApplicationType(ctx.Layers.Path, wr)
-		Expect(err).NotTo(HaveOccurred())
+		w = boot.NewWebApplicationType(boot.JarIndex{}, wr)
 
 		val, ok := w.LayerContributor.ExpectedMetadata.(map[string]interface{})
 		Expect(ok).To(BeTrue())
-		Expect(val["files"]).To(HaveLen(64))
+		Expect(val).To(HaveKey("jars"))
+		Expect(val).To(HaveKey("indicators"))
+		Expect(val).To(HaveKey("configuration"))
 	})
 
 	it.After(func() {
@@ -112,4 +115,183 @@ func testWebApplicationType(t *testing.T, context spec.G, it spec.S) {
 		Expect(layer.LaunchEnvironment["BPL_JVM_THREAD_COUNT.default"]).To(Equal("250"))
 	})
 
+	it("contributes configured application type configuration", func() {
+		w.Resolver.Classes[boot.JakartaServlet] = nil
+		w.Resolver.Classes[boot.ConfigurableWebApplicationContextIndicatorClass] = nil
+		w.Resolver.Configuration = boot.ApplicationConfiguration{Properties: map[string]string{"spring.main.web-application-type": "none"}}
+
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = w.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.Launch).To(BeTrue())
+		Expect(layer.LaunchEnvironment["BPL_JVM_THREAD_COUNT.default"]).To(Equal("50"))
+	})
+
+	it("logs where the application type was detected from when the layer is reused", func() {
+		w.Resolver.Configuration = boot.ApplicationConfiguration{Properties: map[string]string{"spring.main.web-application-type": "none"}}
+
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = w.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		out := &bytes.Buffer{}
+		w.Logger = bard.NewLogger(out)
+
+		_, err = w.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(out.String()).To(ContainSubstring("Reusing"))
+		Expect(out.String()).To(ContainSubstring("Non-web application detected from spring.main.web-application-type"))
+	})
+
+	it("contributes loaded class count", func() {
+		w.LoadedClassCount = 12345
+
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = w.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LaunchEnvironment["BPL_JVM_LOADED_CLASS_COUNT.default"]).To(Equal("12345"))
+	})
+
+	it("does not contribute loaded class count without an estimate", func() {
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = w.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LaunchEnvironment).NotTo(HaveKey("BPL_JVM_LOADED_CLASS_COUNT.default"))
+	})
+
+	it("reserves head room for reactive Netty applications", func() {
+		w.Resolver.Classes[boot.WebFluxIndicatorClass] = nil
+		w.Resolver.Classes[boot.Netty.Class] = nil
+
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = w.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LaunchEnvironment["BPL_JVM_THREAD_COUNT.default"]).To(Equal("50"))
+		Expect(layer.LaunchEnvironment["BPL_JVM_HEAD_ROOM.default"]).To(Equal("10"))
+	})
+
+	it("does not reserve head room for reactive applications on a servlet container", func() {
+		w.Resolver.Classes[boot.WebFluxIndicatorClass] = nil
+		w.Resolver.Classes[boot.Tomcat.Class] = nil
+
+		layer, err := ctx.Layers.Layer("test-layer")
+		Expect(err).NotTo(HaveOccurred())
+
+		layer, err = w.Contribute(layer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(layer.LaunchEnvironment).NotTo(HaveKey("BPL_JVM_HEAD_ROOM.default"))
+	})
+
+	context("servlet thread count", func() {
+		it.Before(func() {
+			w.Resolver.Classes[boot.JakartaServlet] = nil
+			w.Resolver.Classes[boot.ConfigurableWebApplicationContextIndicatorClass] = nil
+			w.Resolver.Configuration = boot.ApplicationConfiguration{Properties: map[string]string{}}
+		})
+
+		it("uses the configured server thread pool", func() {
+			w.Resolver.Configuration.Properties["server.tomcat.threads.max"] = "400"
+
+			layer, err := ctx.Layers.Layer("test-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			layer, err = w.Contribute(layer)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(layer.LaunchEnvironment["BPL_JVM_THREAD_COUNT.default"]).To(Equal("450"))
+			Expect(layer.Metadata["thread-count"]).To(BeEquivalentTo(450))
+		})
+
+		it("uses the thread pool of the embedded server", func() {
+			w.Resolver.Classes[boot.Undertow.Class] = nil
+			w.Resolver.Configuration.Properties["server.tomcat.threads.max"] = "400"
+			w.Resolver.Configuration.Properties["server.undertow.threads.worker"] = "64"
+
+			threads, reason := w.ThreadCount()
+			Expect(threads).To(Equal(114))
+			Expect(reason).To(Equal("server.undertow.threads.worker"))
+		})
+
+		it("uses legacy thread pool properties", func() {
+			w.Resolver.Configuration.Properties["server.jetty.max-threads"] = "100"
+
+			threads, reason := w.ThreadCount()
+			Expect(threads).To(Equal(150))
+			Expect(reason).To(Equal("server.jetty.max-threads"))
+		})
+
+		it("uses fewer threads with virtual threads on Java 21", func() {
+			w.JavaVersion = 21
+			w.Resolver.Configuration.Properties["server.tomcat.threads.max"] = "400"
+			w.Resolver.Configuration.Properties["spring.threads.virtual.enabled"] = "true"
+
+			threads, reason := w.ThreadCount()
+			Expect(threads).To(Equal(50))
+			Expect(reason).To(Equal("spring.threads.virtual.enabled"))
+		})
+
+		it("uses the default thread pool of the embedded server", func() {
+			w.Resolver.Classes[boot.Undertow.Class] = nil
+
+			threads, reason := w.ThreadCount()
+			Expect(threads).To(Equal(114))
+			Expect(reason).To(Equal("the default undertow thread pool"))
+		})
+
+		it("adds threads for other workloads", func() {
+			w.Resolver.Classes["io.grpc.netty.NettyServerBuilder"] = nil
+			w.Resolver.Classes["org.springframework.kafka.annotation.KafkaListener"] = nil
+
+			threads, reason := w.ThreadCount()
+			Expect(threads).To(Equal(325))
+			Expect(reason).To(Equal("the grpc workload and the messaging workload"))
+		})
+
+		it("ignores virtual threads before Java 21", func() {
+			w.JavaVersion = 17
+			w.Resolver.Configuration.Properties["spring.threads.virtual.enabled"] = "true"
+
+			threads, reason := w.ThreadCount()
+			Expect(threads).To(Equal(250))
+			Expect(reason).To(BeEmpty())
+		})
+	})
+
+	it("builds the cache key from jar digests, indicator classes and configuration", func() {
+		wr := boot.WebApplicationTypeResolver{
+			Classes: map[string]interface{}{
+				boot.JakartaServlet:       nil,
+				"com.example.Application": nil,
+			},
+			Configuration: boot.ApplicationConfiguration{Properties: map[string]string{
+				"server.tomcat.threads.max": "400",
+				"server.port":               "9090",
+			}},
+		}
+		index := boot.JarIndex{Jars: []boot.JarIndexEntry{{Path: filepath.Join("lib", "test-1.0.0.jar"), SHA256: "test-sha256"}}}
+
+		w = boot.NewWebApplicationType(index, wr)
+
+		Expect(w.LayerContributor.ExpectedMetadata).To(Equal(map[string]interface{}{
+			"jars":          map[string]interface{}{"test-1.0.0.jar": "test-sha256"},
+			"indicators":    []string{boot.JakartaServlet},
+			"configuration": map[string]interface{}{"server.tomcat.threads.max": "400"},
+		}))
+	})
 }
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"strings"
+
+	"github.com/buildpacks/libcnb"
+)
+
+const LabelWorkloads = "org.springframework.boot.workloads"
+
+// Workload is a kind of traffic an application serves or consumes.
+type Workload string
+
+const (
+	HTTPWorkload      Workload = "http"
+	GRPCWorkload      Workload = "grpc"
+	RSocketWorkload   Workload = "rsocket"
+	MessagingWorkload Workload = "messaging"
+)
+
+// WorkloadIndicatorClasses are the classes whose presence identifies a workload other than HTTP.
+var WorkloadIndicatorClasses = map[Workload][]string{
+	GRPCWorkload: {
+		"org.springframework.grpc.server.GrpcServerFactory",
+		"net.devh.boot.grpc.server.serverfactory.GrpcServerFactory",
+		"io.grpc.netty.NettyServerBuilder",
+		"io.grpc.netty.shaded.io.grpc.netty.NettyServerBuilder",
+	},
+	RSocketWorkload: {
+		"io.rsocket.core.RSocketServer",
+	},
+	MessagingWorkload: {
+		"org.springframework.kafka.annotation.KafkaListener",
+		"org.springframework.amqp.rabbit.annotation.RabbitListener",
+		"org.springframework.jms.annotation.JmsListener",
+		"org.springframework.pulsar.annotation.PulsarListener",
+		"org.springframework.cloud.stream.function.StreamBridge",
+	},
+}
+
+// WorkloadThreads are the platform threads each workload adds to an application by default: gRPC serves calls on an
+// unbounded executor and message listener containers each run their own consumer threads.
+var WorkloadThreads = map[Workload]int{
+	GRPCWorkload:      50,
+	MessagingWorkload: 25,
+}
+
+// Workloads returns the workloads of the application, based on its application type and the classes present.
+func (w WebApplicationTypeResolver) Workloads() []Workload {
+	var workloads []Workload
+
+	if w.Resolve() != None {
+		workloads = append(workloads, HTTPWorkload)
+	}
+
+	for _, workload := range []Workload{GRPCWorkload, RSocketWorkload, MessagingWorkload} {
+		for _, class := range WorkloadIndicatorClasses[workload] {
+			if w.isPresent(class) {
+				workloads = append(workloads, workload)
+				break
+			}
+		}
+	}
+
+	return workloads
+}
+
+// WorkloadsLabel returns an image label listing workloads, or none when there are none.
+func WorkloadsLabel(workloads []Workload) libcnb.Label {
+	if len(workloads) == 0 {
+		return libcnb.Label{Key: LabelWorkloads, Value: "none"}
+	}
+
+	s := make([]string, len(workloads))
+	for i, w := range workloads {
+		s[i] = string(w)
+	}
+
+	return libcnb.Label{Key: LabelWorkloads, Value: strings.Join(s, ",")}
+}
+
+// DefaultProcessType returns the process type that should be launched by default. Applications that only consume
+// messages are workers rather than web processes.
+func DefaultProcessType(workloads []Workload) string {
+	if len(workloads) == 1 && workloads[0] == MessagingWorkload {
+		return "worker"
+	}
+	return "web"
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot_test
+
+import (
+	"testing"
+
+	"github.com/buildpacks/libcnb"
+	. "github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+
+	"github.com/paketo-buildpacks/spring-boot/v5/boot"
+)
+
+func testWorkload(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		w boot.WebApplicationTypeResolver
+	)
+
+	it.Before(func() {
+		w = boot.WebApplicationTypeResolver{Classes: map[string]interface{}{}}
+	})
+
+	it("detects no workloads", func() {
+		Expect(w.Workloads()).To(BeEmpty())
+	})
+
+	it("detects HTTP workload", func() {
+		w.Classes[boot.WebFluxIndicatorClass] = nil
+
+		Expect(w.Workloads()).To(Equal([]boot.Workload{boot.HTTPWorkload}))
+	})
+
+	it("detects gRPC, RSocket and messaging workloads", func() {
+		w.Classes["io.grpc.netty.shaded.io.grpc.netty.NettyServerBuilder"] = nil
+		w.Classes["io.rsocket.core.RSocketServer"] = nil
+		w.Classes["org.springframework.kafka.annotation.KafkaListener"] = nil
+		w.Classes["org.springframework.amqp.rabbit.annotation.RabbitListener"] = nil
+
+		Expect(w.Workloads()).To(Equal([]boot.Workload{boot.GRPCWorkload, boot.RSocketWorkload, boot.MessagingWorkload}))
+	})
+
+	it("creates label", func() {
+		Expect(boot.WorkloadsLabel(nil)).To(Equal(libcnb.Label{Key: "org.springframework.boot.workloads", Value: "none"}))
+		Expect(boot.WorkloadsLabel([]boot.Workload{boot.HTTPWorkload, boot.MessagingWorkload})).
+			To(Equal(libcnb.Label{Key: "org.springframework.boot.workloads", Value: "http,messaging"}))
+	})
+
+	it("selects default process type", func() {
+		Expect(boot.DefaultProcessType(nil)).To(Equal("web"))
+		Expect(boot.DefaultProcessType([]boot.Workload{boot.MessagingWorkload})).To(Equal("worker"))
+		Expect(boot.DefaultProcessType([]boot.Workload{boot.HTTPWorkload, boot.MessagingWorkload})).To(Equal("web"))
+		Expect(boot.DefaultProcessType([]boot.Workload{boot.GRPCWorkload})).To(Equal("web"))
+	})
+}